@@ -0,0 +1,90 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Types of the tasks returned by ActiveTasks.
+const (
+	TaskIndexer            = "indexer"             // A view index being built or updated
+	TaskSearchIndexer      = "search_indexer"      // A search index being built or updated
+	TaskDatabaseCompaction = "database_compaction" // A database being compacted
+	TaskViewCompaction     = "view_compaction"     // The view indexes of a design document being compacted
+	TaskReplication        = "replication"         // A running replication
+)
+
+// ActiveTask is a task running on the server, as returned by ActiveTasks.
+// The fields shared by all tasks are always set; the others depend on the Type of the task.
+type ActiveTask struct {
+	Type      string `json:"type"` // One of the Task* types, e.g. TaskIndexer
+	Node      string `json:"node"`
+	PID       string `json:"pid"`
+	StartedOn int64  `json:"started_on"` // Unix time the task started at
+	UpdatedOn int64  `json:"updated_on"` // Unix time of the last progress update of the task
+
+	// Indexers and compactions.
+	Database       string `json:"database,omitempty"`        // Shard of the database being processed, e.g. "shards/00000000-1fffffff/orders.1588"
+	DesignDocument string `json:"design_document,omitempty"` // Design document being indexed or compacted
+	Progress       int    `json:"progress,omitempty"`        // Completion percentage, from 0 to 100
+	ChangesDone    int    `json:"changes_done,omitempty"`    // Number of changes processed
+	TotalChanges   int    `json:"total_changes,omitempty"`   // Number of changes to process
+
+	// Replications.
+	ReplicationID         string `json:"replication_id,omitempty"`
+	DocID                 string `json:"doc_id,omitempty"` // ID of the replication document; empty if it was started with Replicate
+	Source                string `json:"source,omitempty"`
+	Target                string `json:"target,omitempty"`
+	Continuous            bool   `json:"continuous,omitempty"`
+	ChangesPending        int    `json:"changes_pending,omitempty"`
+	DocsRead              int    `json:"docs_read,omitempty"`
+	DocsWritten           int    `json:"docs_written,omitempty"`
+	DocWriteFailures      int    `json:"doc_write_failures,omitempty"`
+	CheckpointedSourceSeq Seq    `json:"checkpointed_source_seq,omitempty"`
+	SourceSeq             Seq    `json:"source_seq,omitempty"`
+}
+
+// ActiveTasks returns the tasks running on the server, such as view indexing, compactions and replications.
+//
+// Returns:
+//   - []ActiveTask: The running tasks; indexing and compaction tasks are reported once per shard.
+//   - error: An error, if any, encountered while getting the tasks.
+//
+// Example:
+//
+//	// Wait for the views of the "orders" database to be built before routing traffic to the new version.
+//	for {
+//	    tasks, err := cs.ActiveTasks(ctx)
+//	    if err != nil {
+//	        log.Fatalf("Error getting active tasks: %v", err)
+//	    }
+//	    indexing := false
+//	    for _, task := range tasks {
+//	        if task.Type == couchdb.TaskIndexer && strings.Contains(task.Database, "/orders.") {
+//	            indexing = true
+//	        }
+//	    }
+//	    if !indexing {
+//	        break
+//	    }
+//	    time.Sleep(5 * time.Second)
+//	}
+func (c *CouchService) ActiveTasks(ctx context.Context) ([]ActiveTask, error) {
+	respCode, respBody, err := c.httpClient.Get(ctx, "_active_tasks")
+	if err != nil {
+		return nil, fmt.Errorf("error getting active tasks: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting active tasks", respCode, respBody)
+	}
+
+	var tasks []ActiveTask
+	err = json.Unmarshal(respBody, &tasks)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling active tasks: %w", err)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,56 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestActiveTasks(t *testing.T) {
+	var status int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_active_tasks" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if status != 0 {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":"unauthorized","reason":"You are not a server admin."}`))
+			return
+		}
+		w.Write([]byte(`[
+			{"type":"indexer","node":"node1@127.0.0.1","pid":"<0.1.0>","started_on":1714557600,"updated_on":1714557605,
+			 "database":"shards/00000000-7fffffff/orders.1588","design_document":"_design/orders","progress":40,"changes_done":400,"total_changes":1000},
+			{"type":"database_compaction","node":"node1@127.0.0.1","pid":"<0.2.0>","database":"shards/80000000-ffffffff/orders.1588","progress":90},
+			{"type":"replication","node":"node1@127.0.0.1","pid":"<0.3.0>","replication_id":"a81a+continuous","doc_id":"orders-backup",
+			 "continuous":true,"changes_pending":12,"docs_written":30,"checkpointed_source_seq":"30-g1AAAA"}
+		]`))
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	tasks, err := cs.ActiveTasks(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("Expected 3 tasks, got %d", len(tasks))
+	}
+	if indexer := tasks[0]; indexer.Type != TaskIndexer || indexer.DesignDocument != "_design/orders" || indexer.Progress != 40 || indexer.TotalChanges != 1000 {
+		t.Errorf("Unexpected indexer task %+v", indexer)
+	}
+	if compaction := tasks[1]; compaction.Type != TaskDatabaseCompaction || compaction.Progress != 90 {
+		t.Errorf("Unexpected compaction task %+v", compaction)
+	}
+	if replication := tasks[2]; replication.Type != TaskReplication || replication.DocID != "orders-backup" || replication.ChangesPending != 12 || replication.CheckpointedSourceSeq != "30-g1AAAA" {
+		t.Errorf("Unexpected replication task %+v", replication)
+	}
+
+	status = http.StatusUnauthorized
+	if _, err := cs.ActiveTasks(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// AllDocsOptions are the query options of the _all_docs endpoint.
+type AllDocsOptions struct {
+	StartKey    string // Return documents starting with this ID
+	EndKey      string // Stop returning documents after this ID
+	Limit       int    // Maximum number of rows to return; 0 means no limit
+	Skip        int    // Number of rows to skip
+	Descending  bool   // Return rows in descending ID order; StartKey and EndKey must be swapped accordingly
+	IncludeDocs bool   // Include the full document in each row
+	Conflicts   bool   // Include the _conflicts field of each document; only used together with IncludeDocs
+}
+
+// query encodes the options as query parameters, JSON-encoding the keys as CouchDB expects.
+func (o AllDocsOptions) query() (url.Values, error) {
+	values := url.Values{}
+	if o.StartKey != "" {
+		startKey, err := json.Marshal(o.StartKey)
+		if err != nil {
+			return nil, err
+		}
+		values.Set("startkey", string(startKey))
+	}
+	if o.EndKey != "" {
+		endKey, err := json.Marshal(o.EndKey)
+		if err != nil {
+			return nil, err
+		}
+		values.Set("endkey", string(endKey))
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Skip > 0 {
+		values.Set("skip", strconv.Itoa(o.Skip))
+	}
+	if o.Descending {
+		values.Set("descending", "true")
+	}
+	if o.IncludeDocs {
+		values.Set("include_docs", "true")
+		if o.Conflicts {
+			values.Set("conflicts", "true")
+		}
+	}
+	return values, nil
+}
+
+// AllDocsResponse defines a struct to represent the response JSON object returned from the _all_docs endpoint.
+// It can be used as a generic resultVar in AllDocs.
+type AllDocsResponse struct {
+	TotalRows int          `json:"total_rows"`
+	Offset    int          `json:"offset"`
+	Rows      []AllDocsRow `json:"rows"`
+}
+
+// AllDocsRow is a single row of an _all_docs response.
+type AllDocsRow struct {
+	ID    string          `json:"id"`
+	Key   string          `json:"key"`
+	Value AllDocsValue    `json:"value"`
+	Doc   json.RawMessage `json:"doc"`             // Only set when IncludeDocs is requested
+	Error string          `json:"error,omitempty"` // Set instead of the other fields when a requested key doesn't exist
+}
+
+// AllDocsValue is the value of an _all_docs row, holding the current revision of the document.
+type AllDocsValue struct {
+	Rev     string `json:"rev"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// AllDocs lists the documents of the database through the _all_docs endpoint, without needing a view.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - opts: The query options.
+//   - resultVar: A pointer to a struct where the results will be unmarshalled, with the same requirements as in View:
+//     it must have a "rows" field holding a slice of structs with "id" and "key" JSON fields, plus a "doc" JSON field
+//     if opts.IncludeDocs is true. AllDocsResponse can be used as a generic result.
+//   - reqOpts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - error: An error if the query fails or if resultVar does not meet the requirements.
+//
+// Example:
+//
+//	var result couchdb.AllDocsResponse
+//	err := db.AllDocs(ctx, couchdb.AllDocsOptions{StartKey: "user:", EndKey: "user:￰", Limit: 50}, &result)
+//	if err != nil {
+//	    log.Fatalf("Error listing documents: %v", err)
+//	}
+func (db *Database) AllDocs(ctx context.Context, opts AllDocsOptions, resultVar any, reqOpts ...RequestOption) error {
+	return db.queryAllDocs(ctx, fmt.Sprintf("%s/_all_docs", db.dbName), opts, nil, resultVar, reqOpts...)
+}
+
+// GetDocs fetches an arbitrary set of documents by ID in a single request, by POSTing the keys to _all_docs.
+//
+// Rows are returned in the same order as ids. IDs that don't exist are not an error: their row has its
+// "error" field set to "not_found"; deleted documents have a null "doc" and their value reports deleted: true.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - ids: The IDs of the documents to fetch.
+//   - includeDocs: Whether to include the full documents, or only their current revisions.
+//   - resultVar: A pointer to a struct where the results will be unmarshalled, with the same requirements as in AllDocs.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - error: An error if the request fails or if resultVar does not meet the requirements.
+//
+// Example:
+//
+//	var result struct {
+//	    Rows []struct {
+//	        ID    string `json:"id"`
+//	        Key   string `json:"key"`
+//	        Doc   Person `json:"doc"`
+//	        Error string `json:"error"`
+//	    } `json:"rows"`
+//	}
+//	err := db.GetDocs(ctx, []string{"john", "jane"}, true, &result)
+//	if err != nil {
+//	    log.Fatalf("Error getting documents: %v", err)
+//	}
+func (db *Database) GetDocs(ctx context.Context, ids []string, includeDocs bool, resultVar any, opts ...RequestOption) error {
+	body := map[string]any{"keys": ids}
+	return db.queryAllDocs(ctx, fmt.Sprintf("%s/_all_docs", db.dbName), AllDocsOptions{IncludeDocs: includeDocs}, body, resultVar, opts...)
+}
+
+// DesignDocs lists the design documents of the database through the _design_docs endpoint.
+// It accepts the same options as AllDocs, including per-request options; keys include the "_design/" prefix, e.g. StartKey: "_design/a".
+//
+// Example:
+//
+//	designDocs, err := db.DesignDocs(ctx, couchdb.AllDocsOptions{})
+//	if err != nil {
+//	    log.Fatalf("Error listing design documents: %v", err)
+//	}
+//	for _, row := range designDocs.Rows {
+//	    fmt.Println(row.ID, row.Value.Rev)
+//	}
+func (db *Database) DesignDocs(ctx context.Context, opts AllDocsOptions, reqOpts ...RequestOption) (*AllDocsResponse, error) {
+	var designDocs AllDocsResponse
+	err := db.queryAllDocs(ctx, fmt.Sprintf("%s/_design_docs", db.dbName), opts, nil, &designDocs, reqOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &designDocs, nil
+}
+
+// queryAllDocs queries an endpoint that shares the _all_docs response format and unmarshals the result into resultVar.
+// The request is a GET, or a POST of body when it isn't nil.
+func (db *Database) queryAllDocs(ctx context.Context, endpoint string, opts AllDocsOptions, body any, resultVar any, reqOpts ...RequestOption) error {
+	err := checkStructForJSONFields(resultVar)
+	if err != nil {
+		return fmt.Errorf("error checking struct for JSON fields: %w", err)
+	}
+
+	values, err := opts.query()
+	if err != nil {
+		return fmt.Errorf("error encoding all docs options: %w", err)
+	}
+
+	var respCode int
+	var respBody []byte
+	if body != nil {
+		respCode, respBody, err = db.httpClient.Post(ctx, withQuery(endpoint, values), body, reqOpts...)
+	} else {
+		respCode, respBody, err = db.httpClient.Get(ctx, withQuery(endpoint, values), reqOpts...)
+	}
+	if err != nil {
+		return fmt.Errorf("error getting all docs: %w", err)
+	}
+
+	if respCode != 200 {
+		return responseError("error getting all docs", respCode, respBody)
+	}
+
+	err = json.Unmarshal(respBody, resultVar)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling into resultVar: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAllDocsOptionsQuery(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     AllDocsOptions
+		expected string
+	}{
+		{name: "no options", opts: AllDocsOptions{}, expected: ""},
+		{
+			name:     "key range is JSON encoded",
+			opts:     AllDocsOptions{StartKey: "user:", EndKey: "user:￰"},
+			expected: "endkey=%22user%3A%EF%BF%B0%22&startkey=%22user%3A%22",
+		},
+		{
+			name:     "paging and ordering",
+			opts:     AllDocsOptions{Limit: 10, Skip: 20, Descending: true},
+			expected: "descending=true&limit=10&skip=20",
+		},
+		{
+			name:     "docs with conflicts",
+			opts:     AllDocsOptions{IncludeDocs: true, Conflicts: true},
+			expected: "conflicts=true&include_docs=true",
+		},
+		{name: "conflicts without docs", opts: AllDocsOptions{Conflicts: true}, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := tc.opts.query()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := values.Encode(); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAllDocsResponseMeetsResultRequirements(t *testing.T) {
+	if err := checkStructForJSONFields(&AllDocsResponse{}); err != nil {
+		t.Errorf("Expected AllDocsResponse to be a valid resultVar, got %v", err)
+	}
+}
+
+func TestGetDocs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/test/_all_docs" || r.URL.Query().Get("include_docs") != "true" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		var body struct {
+			Keys []string `json:"keys"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Keys) != 2 {
+			t.Errorf("Unexpected body: %v, %v", body, err)
+		}
+		w.Write([]byte(`{"total_rows":1,"offset":0,"rows":[
+			{"id":"a","key":"a","value":{"rev":"1-x"},"doc":{"_id":"a","_rev":"1-x"}},
+			{"key":"missing","error":"not_found"}]}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	var result AllDocsResponse
+	if err := db.GetDocs(context.Background(), []string{"a", "missing"}, true, &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Rows) != 2 || result.Rows[0].Value.Rev != "1-x" || result.Rows[1].Error != "not_found" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestDesignDocs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		statusCode    int
+		body          string
+		expectedIDs   []string
+		expectedError error
+	}{
+		{
+			name:       "design documents",
+			statusCode: 200,
+			body: `{"total_rows":3,"offset":1,"rows":[
+				{"id":"_design/orders","key":"_design/orders","value":{"rev":"2-a"}},
+				{"id":"_design/users","key":"_design/users","value":{"rev":"1-b"}}]}`,
+			expectedIDs: []string{"_design/orders", "_design/users"},
+		},
+		{name: "missing database", statusCode: 404, body: `{"error":"not_found","reason":"Database does not exist."}`, expectedError: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				if r.Method != http.MethodGet || r.URL.Path != "/test/_design_docs" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+				}
+				if query.Get("startkey") != `"_design/o"` || query.Get("limit") != "2" {
+					t.Errorf("Unexpected query %v", query)
+				}
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			designDocs, err := db.DesignDocs(context.Background(), AllDocsOptions{StartKey: "_design/o", Limit: 2})
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if tc.expectedError != nil {
+				return
+			}
+
+			var ids []string
+			for _, row := range designDocs.Rows {
+				ids = append(ids, row.ID)
+			}
+			if !reflect.DeepEqual(ids, tc.expectedIDs) || designDocs.TotalRows != 3 || designDocs.Rows[0].Value.Rev != "2-a" {
+				t.Errorf("Unexpected design documents %+v", designDocs)
+			}
+		})
+	}
+}
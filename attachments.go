@@ -0,0 +1,335 @@
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Attachment describes a document attachment, as found in the _attachments field of a document.
+type Attachment struct {
+	ContentType   string `json:"content_type"`
+	Data          []byte `json:"data,omitempty"` // Attachment content; only set when requested with GetDocOptions.Attachments
+	Digest        string `json:"digest,omitempty"`
+	Length        int64  `json:"length,omitempty"`
+	RevPos        int    `json:"revpos,omitempty"`
+	Stub          bool   `json:"stub,omitempty"` // Whether the content was left out
+	Encoding      string `json:"encoding,omitempty"`
+	EncodedLength int64  `json:"encoded_length,omitempty"`
+}
+
+// attachmentResponse is the response returned by CouchDB when an attachment is written or deleted.
+type attachmentResponse struct {
+	ID  string `json:"id"`
+	Ok  bool   `json:"ok"`
+	Rev string `json:"rev"`
+}
+
+// PutAttachment stores binary data as an attachment of a document, creating the document if it doesn't exist.
+//
+// The data is streamed from r as it is sent, so attachments of any size are uploaded with constant memory.
+// As r can only be read once, the upload is not retried on transient failures.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - docID: The ID of the document the attachment belongs to.
+//   - rev: The current revision of the document; empty when creating a new document.
+//   - name: The name of the attachment.
+//   - contentType: The MIME type of the attachment, e.g. "image/png".
+//   - r: The attachment data.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - string: The new revision of the document.
+//   - error: An error, if any, encountered while reading or storing the attachment.
+//
+// Example:
+//
+//	f, err := os.Open("avatar.png")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer f.Close()
+//
+//	newRev, err := db.PutAttachment(ctx, "user:jane", currentRev, "avatar.png", "image/png", f)
+//	if err != nil {
+//	    log.Fatalf("Error storing attachment: %v", err)
+//	}
+func (db *Database) PutAttachment(ctx context.Context, docID, rev, name, contentType string, r io.Reader, opts ...RequestOption) (string, error) {
+	values := url.Values{}
+	if rev != "" {
+		values.Set("rev", rev)
+	}
+
+	req := &request{
+		method:      "PUT",
+		endpoint:    withQuery(attachmentPath(db.dbName, docID, name), values),
+		bodyReader:  r,
+		contentType: contentType,
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := db.httpClient.do(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("error putting attachment: %w", err)
+	}
+
+	if resp.statusCode != 201 && resp.statusCode != 202 {
+		return "", responseError("error putting attachment", resp.statusCode, resp.body)
+	}
+
+	var putResponse attachmentResponse
+	err = json.Unmarshal(resp.body, &putResponse)
+	if err != nil {
+		return "", fmt.Errorf("error unmarshalling put attachment response: %w", err)
+	}
+
+	return putResponse.Rev, nil
+}
+
+// DeleteAttachment removes an attachment from a document.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - docID: The ID of the document the attachment belongs to.
+//   - rev: The current revision of the document; if empty, CouchDB rejects the deletion with ErrConflict.
+//   - name: The name of the attachment.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - string: The new revision of the document.
+//   - error: An error, if any, encountered while deleting the attachment.
+func (db *Database) DeleteAttachment(ctx context.Context, docID, rev, name string, opts ...RequestOption) (string, error) {
+	values := url.Values{}
+	if rev != "" {
+		values.Set("rev", rev)
+	}
+
+	respCode, respBody, err := db.httpClient.Delete(ctx, withQuery(attachmentPath(db.dbName, docID, name), values), opts...)
+	if err != nil {
+		return "", fmt.Errorf("error deleting attachment: %w", err)
+	}
+
+	if respCode != 200 && respCode != 202 {
+		return "", responseError("error deleting attachment", respCode, respBody)
+	}
+
+	var deleteResponse attachmentResponse
+	err = json.Unmarshal(respBody, &deleteResponse)
+	if err != nil {
+		return "", fmt.Errorf("error unmarshalling delete attachment response: %w", err)
+	}
+
+	return deleteResponse.Rev, nil
+}
+
+// AttachmentInfo is the metadata of an attachment, as reported by the headers of a HEAD request.
+type AttachmentInfo struct {
+	ContentType   string // MIME type of the attachment
+	ContentLength int64  // Size of the attachment as it would be transferred, in bytes
+	Digest        string // Digest of the attachment, in the same "md5-<base64>" form as in document stubs
+	Encoding      string // Content-Encoding the attachment would be transferred with, e.g. "gzip"; empty if none
+}
+
+// AttachmentInfo returns the metadata of an attachment without downloading its content,
+// so callers can decide whether it is worth fetching (e.g. by comparing its digest with a local copy).
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - docID: The ID of the document the attachment belongs to.
+//   - name: The name of the attachment.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - *AttachmentInfo: The attachment metadata.
+//   - error: ErrNotFound if the document or the attachment doesn't exist, or any other error encountered.
+func (db *Database) AttachmentInfo(ctx context.Context, docID, name string, opts ...RequestOption) (*AttachmentInfo, error) {
+	r := &request{
+		method:   "HEAD",
+		endpoint: attachmentPath(db.dbName, docID, name),
+		accept:   "*/*",
+		// Asking for gzip explicitly keeps the Content-Encoding and Content-Length of compressed attachments,
+		// which the transport would otherwise hide by negotiating and decoding gzip itself.
+		header: http.Header{"Accept-Encoding": {"gzip"}},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	resp, err := db.httpClient.do(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("error getting attachment info: %w", err)
+	}
+
+	if resp.statusCode != 200 {
+		return nil, responseError("error getting attachment info", resp.statusCode, nil)
+	}
+
+	return attachmentInfoFromHeader(resp.header), nil
+}
+
+// attachmentInfoFromHeader extracts the attachment metadata from the headers of an attachment response.
+func attachmentInfoFromHeader(header http.Header) *AttachmentInfo {
+	info := &AttachmentInfo{
+		ContentType: header.Get("Content-Type"),
+		Encoding:    header.Get("Content-Encoding"),
+	}
+
+	if length, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil {
+		info.ContentLength = length
+	}
+
+	// The ETag of an attachment is its quoted base64 MD5, also sent as Content-MD5 by most CouchDB versions.
+	digest := header.Get("Content-MD5")
+	if digest == "" {
+		digest = strings.Trim(header.Get("ETag"), `"`)
+	}
+	if digest != "" {
+		info.Digest = "md5-" + digest
+	}
+
+	return info
+}
+
+// AttachmentData is the raw content of an attachment.
+type AttachmentData struct {
+	ContentType string
+	Data        []byte
+}
+
+// GetDocMultipart retrieves a document together with the content of all its attachments, negotiating
+// a multipart/related response so attachments are transferred as raw binary parts instead of base64-inflated JSON.
+//
+// The document itself is unmarshalled into doc, like GetDocWithOptions does; opts.Attachments is always set.
+// Documents without attachments are returned by CouchDB as plain JSON, which is handled transparently.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - id: The ID of the document to retrieve.
+//   - opts: The options of the request; AttsSince can be used to skip attachments the caller already has.
+//   - doc: A pointer to a struct (or map[string]interface{}) where the document data will be populated.
+//   - reqOpts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - map[string]AttachmentData: The content of the attachments, by attachment name.
+//     Attachments left out because of AttsSince are not included.
+//   - error: An error, if any, encountered while retrieving or decoding the document.
+//
+// Example:
+//
+//	var invoice Invoice
+//	attachments, err := db.GetDocMultipart(ctx, "invoice:42", couchdb.GetDocOptions{}, &invoice)
+//	if err != nil {
+//	    log.Fatalf("Error getting document: %v", err)
+//	}
+//	pdf := attachments["invoice.pdf"].Data
+func (db *Database) GetDocMultipart(ctx context.Context, id string, opts GetDocOptions, doc any, reqOpts ...RequestOption) (map[string]AttachmentData, error) {
+	if !isValidParam(doc) {
+		return nil, fmt.Errorf("doc parameter must be a pointer to a struct")
+	}
+
+	opts.Attachments = true
+	values, err := opts.query()
+	if err != nil {
+		return nil, fmt.Errorf("error encoding get doc options: %w", err)
+	}
+
+	r := &request{
+		method:   "GET",
+		endpoint: withQuery(docPath(db.dbName, id), values),
+		accept:   mediaTypeMultipartRelated,
+	}
+	for _, opt := range reqOpts {
+		opt(r)
+	}
+
+	resp, err := db.httpClient.do(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("error getting doc: %w", err)
+	}
+
+	if resp.statusCode != 200 {
+		return nil, responseError("error getting doc", resp.statusCode, resp.body)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(resp.header.Get("Content-Type"))
+	if err != nil || mediaType != mediaTypeMultipartRelated {
+		return decodeInlineAttachments(resp.body, doc)
+	}
+
+	return decodeMultipartDoc(resp.body, params["boundary"], doc)
+}
+
+// decodeInlineAttachments unmarshals a JSON document into doc and extracts the content of its inline attachments.
+func decodeInlineAttachments(body []byte, doc any) (map[string]AttachmentData, error) {
+	if err := json.Unmarshal(body, doc); err != nil {
+		return nil, fmt.Errorf("error unmarshalling doc: %w", err)
+	}
+
+	var attachmentsDoc Document
+	if err := json.Unmarshal(body, &attachmentsDoc); err != nil {
+		return nil, fmt.Errorf("error unmarshalling doc attachments: %w", err)
+	}
+
+	attachments := make(map[string]AttachmentData, len(attachmentsDoc.Attachments))
+	for name, attachment := range attachmentsDoc.Attachments {
+		if attachment.Stub {
+			continue
+		}
+		attachments[name] = AttachmentData{ContentType: attachment.ContentType, Data: attachment.Data}
+	}
+	return attachments, nil
+}
+
+// decodeMultipartDoc parses a multipart/related document response: the first part is the JSON document,
+// unmarshalled into doc, and each following part is an attachment named by its Content-Disposition filename.
+func decodeMultipartDoc(body []byte, boundary string, doc any) (map[string]AttachmentData, error) {
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	docPart, err := reader.NextPart()
+	if err != nil {
+		return nil, fmt.Errorf("error reading multipart doc: %w", err)
+	}
+	if err := json.NewDecoder(docPart).Decode(doc); err != nil {
+		return nil, fmt.Errorf("error unmarshalling doc: %w", err)
+	}
+
+	attachments := make(map[string]AttachmentData)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading multipart attachment: %w", err)
+		}
+
+		// part.FileName() is not used since it strips directories, which are valid in attachment names.
+		_, dispositionParams, err := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+		if err != nil || dispositionParams["filename"] == "" {
+			return nil, fmt.Errorf("multipart attachment without a filename")
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("error reading multipart attachment %q: %w", dispositionParams["filename"], err)
+		}
+
+		attachments[dispositionParams["filename"]] = AttachmentData{
+			ContentType: part.Header.Get("Content-Type"),
+			Data:        data,
+		}
+	}
+
+	return attachments, nil
+}
@@ -0,0 +1,302 @@
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDecodeMultipartDoc(t *testing.T) {
+	body := strings.Join([]string{
+		"--abc",
+		"Content-Type: application/json",
+		"",
+		`{"_id":"doc","_rev":"2-b","_attachments":{"a.txt":{"follows":true},"dir/b.bin":{"follows":true}}}`,
+		"--abc",
+		`Content-Disposition: attachment; filename="a.txt"`,
+		"Content-Type: text/plain",
+		"",
+		"hello",
+		"--abc",
+		`Content-Disposition: attachment; filename="dir/b.bin"`,
+		"Content-Type: application/octet-stream",
+		"",
+		"\x00\x01\x02",
+		"--abc--",
+	}, "\r\n")
+
+	var doc map[string]interface{}
+	attachments, err := decodeMultipartDoc([]byte(body), "abc", &doc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if doc["_id"] != "doc" {
+		t.Errorf("Expected document to be decoded, got %v", doc)
+	}
+
+	expected := map[string]AttachmentData{
+		"a.txt":     {ContentType: "text/plain", Data: []byte("hello")},
+		"dir/b.bin": {ContentType: "application/octet-stream", Data: []byte{0, 1, 2}},
+	}
+	if !reflect.DeepEqual(attachments, expected) {
+		t.Errorf("Expected attachments %v, got %v", expected, attachments)
+	}
+}
+
+func TestDecodeInlineAttachments(t *testing.T) {
+	body := `{"_id":"doc","_attachments":{"a.txt":{"content_type":"text/plain","data":"aGVsbG8="},"b.txt":{"content_type":"text/plain","stub":true}}}`
+
+	var doc Base
+	attachments, err := decodeInlineAttachments([]byte(body), &doc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]AttachmentData{"a.txt": {ContentType: "text/plain", Data: []byte("hello")}}
+	if !reflect.DeepEqual(attachments, expected) {
+		t.Errorf("Expected attachments %v, got %v", expected, attachments)
+	}
+}
+
+func TestPutAttachment(t *testing.T) {
+	testCases := []struct {
+		name             string
+		rev              string
+		statusCode       int
+		body             string
+		expectedQuery    string
+		expectedRev      string
+		expectedError    error
+		expectedRequests int32
+	}{
+		{name: "new document", statusCode: 201, body: `{"ok":true,"id":"doc","rev":"1-a"}`, expectedRev: "1-a", expectedRequests: 1},
+		{name: "existing document", rev: "1-a", statusCode: 201, body: `{"ok":true,"id":"doc","rev":"2-b"}`, expectedQuery: "rev=1-a", expectedRev: "2-b", expectedRequests: 1},
+		{name: "stale revision", rev: "1-a", statusCode: 409, body: `{"error":"conflict","reason":"Document update conflict."}`, expectedQuery: "rev=1-a", expectedError: ErrConflict, expectedRequests: 1},
+		{name: "server error is not retried", statusCode: 500, body: `{"error":"unknown_error","reason":"badarg"}`, expectedError: ErrServerError, expectedRequests: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				if r.Method != http.MethodPut || r.URL.EscapedPath() != "/test/doc/notes%20v2.txt" || r.URL.RawQuery != tc.expectedQuery {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+				}
+				if r.Header.Get("Content-Type") != "text/plain" {
+					t.Errorf("Expected Content-Type text/plain, got %q", r.Header.Get("Content-Type"))
+				}
+				// The data is streamed rather than buffered, so its length isn't known upfront.
+				if r.ContentLength != -1 {
+					t.Errorf("Expected a streamed body, got a Content-Length of %d", r.ContentLength)
+				}
+				if data, _ := io.ReadAll(r.Body); string(data) != "hello attachment" {
+					t.Errorf("Unexpected attachment data %q", data)
+				}
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 3, time.Millisecond, time.Second), dbName: "test"}
+			data := struct{ io.Reader }{strings.NewReader("hello attachment")}
+			rev, err := db.PutAttachment(context.Background(), "doc", tc.rev, "notes v2.txt", "text/plain", data)
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if rev != tc.expectedRev || requests != tc.expectedRequests {
+				t.Errorf("Expected revision %q after %d requests, got %q after %d", tc.expectedRev, tc.expectedRequests, rev, requests)
+			}
+		})
+	}
+}
+
+// failingReader fails after returning its data, like a file on a broken disk.
+type failingReader struct{ data []byte }
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, errors.New("disk failure")
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestPutAttachmentData(t *testing.T) {
+	testCases := []struct {
+		name           string
+		data           io.Reader
+		expectedLength int64
+		expectError    bool
+	}{
+		{name: "data of known size", data: bytes.NewReader([]byte("hello")), expectedLength: 5},
+		{name: "empty data", data: bytes.NewReader(nil), expectedLength: 0},
+		{name: "failing data", data: &failingReader{data: []byte("hel")}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if _, err := io.ReadAll(r.Body); err != nil {
+					return // The client gave up on the upload
+				}
+				if r.ContentLength != tc.expectedLength {
+					t.Errorf("Expected a Content-Length of %d, got %d", tc.expectedLength, r.ContentLength)
+				}
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"ok":true,"id":"doc","rev":"1-a"}`))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			rev, err := db.PutAttachment(context.Background(), "doc", "", "a.bin", "application/octet-stream", tc.data)
+			if tc.expectError {
+				if err == nil || !strings.Contains(err.Error(), "disk failure") {
+					t.Errorf("Expected the read error to be reported, got %v", err)
+				}
+				return
+			}
+			if err != nil || rev != "1-a" {
+				t.Errorf("Expected revision 1-a, got %q, %v", rev, err)
+			}
+		})
+	}
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rev           string
+		statusCode    int
+		body          string
+		expectedQuery string
+		expectedRev   string
+		expectedError error
+	}{
+		{name: "deleted", rev: "2-b", statusCode: 200, body: `{"ok":true,"id":"doc","rev":"3-c"}`, expectedQuery: "rev=2-b", expectedRev: "3-c"},
+		{name: "missing revision", statusCode: 409, body: `{"error":"conflict","reason":"Document update conflict."}`, expectedError: ErrConflict},
+		{name: "missing attachment", rev: "2-b", statusCode: 404, body: `{"error":"not_found","reason":"Document is missing attachment"}`, expectedQuery: "rev=2-b", expectedError: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete || r.URL.EscapedPath() != "/test/doc/dir%2Fa.txt" || r.URL.RawQuery != tc.expectedQuery {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+				}
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			rev, err := db.DeleteAttachment(context.Background(), "doc", tc.rev, "dir/a.txt")
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if rev != tc.expectedRev {
+				t.Errorf("Expected revision %q, got %q", tc.expectedRev, rev)
+			}
+		})
+	}
+}
+
+func TestAttachmentInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" {
+			t.Errorf("Expected HEAD, got %s", r.Method)
+		}
+		if r.URL.EscapedPath() != "/test/doc/notes.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("Expected Accept-Encoding gzip, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", "31")
+		w.Header().Set("ETag", `"abc=="`)
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name        string
+		attachment  string
+		expected    AttachmentInfo
+		expectedErr error
+	}{
+		{
+			name:       "compressed attachment",
+			attachment: "notes.txt",
+			expected:   AttachmentInfo{ContentType: "text/plain", ContentLength: 31, Digest: "md5-abc==", Encoding: "gzip"},
+		},
+		{name: "missing attachment", attachment: "missing.txt", expectedErr: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			info, err := db.AttachmentInfo(context.Background(), "doc", tc.attachment)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+			if tc.expectedErr == nil && *info != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, *info)
+			}
+		})
+	}
+}
+
+func TestAttachmentInfoFromHeader(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   http.Header
+		expected AttachmentInfo
+	}{
+		{
+			name: "content MD5",
+			header: http.Header{
+				"Content-Type":   {"image/png"},
+				"Content-Length": {"2048"},
+				"Content-Md5":    {"DBMmslR+9bCmQbrwqBh2Mg=="},
+				"Etag":           {`"DBMmslR+9bCmQbrwqBh2Mg=="`},
+			},
+			expected: AttachmentInfo{ContentType: "image/png", ContentLength: 2048, Digest: "md5-DBMmslR+9bCmQbrwqBh2Mg=="},
+		},
+		{
+			name: "digest from ETag and encoding",
+			header: http.Header{
+				"Content-Type":     {"text/plain"},
+				"Content-Length":   {"31"},
+				"Content-Encoding": {"gzip"},
+				"Etag":             {`"abc=="`},
+			},
+			expected: AttachmentInfo{ContentType: "text/plain", ContentLength: 31, Digest: "md5-abc==", Encoding: "gzip"},
+		},
+		{
+			name:     "no headers",
+			header:   http.Header{},
+			expected: AttachmentInfo{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := attachmentInfoFromHeader(tc.header); *got != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, *got)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionCookieName is the name of the cookie CouchDB uses to identify an authenticated session.
+const sessionCookieName = "AuthSession"
+
+// sessionRenewMargin is how long before the expiry of its cookie a session is renewed.
+const sessionRenewMargin = time.Minute
+
+// Authenticator attaches credentials to the requests made by a client, for the schemes that don't embed them
+// in the base URL. It is consulted before every attempt of every request; the request context is available
+// through req.Context().
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// Refresher is optionally implemented by Authenticators whose credentials can expire.
+// When the server rejects a request with 401 Unauthorized, Refresh is called and the request is retried once.
+type Refresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// WithAuthenticator authenticates every request with a, which can implement any custom scheme.
+// It replaces the authentication selected by any previous option, and the credentials passed to GetInstance are ignored.
+func WithAuthenticator(a Authenticator) Option {
+	return func(c *CustomHTTPClient) {
+		c.auth = a
+	}
+}
+
+// cookieUpdater is implemented by authenticators that track cookies set by the server.
+type cookieUpdater interface {
+	update(cookies []*http.Cookie)
+}
+
+// WithBasicAuth sends the credentials passed to GetInstance in an Authorization: Basic header,
+// instead of embedding them in the base URL, where they end up in error messages and proxy logs.
+func WithBasicAuth() Option {
+	return func(c *CustomHTTPClient) {
+		c.auth = &basicAuth{}
+	}
+}
+
+// basicAuth authenticates requests with HTTP basic authentication.
+type basicAuth struct {
+	username string
+	password string
+}
+
+func (b *basicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(b.username, b.password)
+	return nil
+}
+
+// WithCookieAuth authenticates with a session cookie obtained from the _session endpoint, instead of embedding
+// the credentials passed to GetInstance in the base URL, where they tend to leak into logs and error messages.
+//
+// The session is opened lazily on the first request, and its cookie is then attached to every request made
+// through the service. The cookie refreshed by CouchDB on active sessions is picked up from the responses, and the
+// session is renewed shortly before its cookie expires, or when the server rejects it with a 401, in which case
+// the rejected request is retried once.
+func WithCookieAuth() Option {
+	return func(c *CustomHTTPClient) {
+		c.auth = &cookieSession{client: c}
+	}
+}
+
+// cookieSession holds the credentials and the current session cookie of a client using cookie authentication.
+// It is shared by all the copies of a client, so a session opened by one database handle is reused by the others.
+type cookieSession struct {
+	client   *CustomHTTPClient
+	username string
+	password string
+
+	mu      sync.Mutex
+	cookie  *http.Cookie
+	expires time.Time // Expiry of cookie; zero if the server didn't say
+}
+
+// Authenticate attaches the session cookie to req, opening a session first if there is none yet.
+func (s *cookieSession) Authenticate(req *http.Request) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cookie == nil || (!s.expires.IsZero() && time.Until(s.expires) < sessionRenewMargin) {
+		cookie, err := s.login(req.Context())
+		if err != nil {
+			return err
+		}
+		s.setCookie(cookie)
+	}
+
+	req.AddCookie(s.cookie)
+	return nil
+}
+
+// update stores the session cookie refreshed by the server in a response, if any.
+func (s *cookieSession) update(cookies []*http.Cookie) {
+	for _, cookie := range cookies {
+		if cookie.Name == sessionCookieName && cookie.Value != "" {
+			s.mu.Lock()
+			s.setCookie(cookie)
+			s.mu.Unlock()
+			return
+		}
+	}
+}
+
+// Refresh discards the current session cookie, so the next request opens a new session.
+func (s *cookieSession) Refresh(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cookie = nil
+	return nil
+}
+
+// setCookie stores cookie as the current session cookie along with its expiry. s.mu must be held.
+func (s *cookieSession) setCookie(cookie *http.Cookie) {
+	s.cookie = cookie
+	switch {
+	case cookie.MaxAge > 0:
+		s.expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+	case !cookie.Expires.IsZero():
+		s.expires = cookie.Expires
+	default:
+		s.expires = time.Time{}
+	}
+}
+
+// login opens a new session with the stored credentials and returns its cookie.
+// It talks to the server directly rather than through do, so it is neither retried nor authenticated itself.
+func (s *cookieSession) login(ctx context.Context) (*http.Cookie, error) {
+	c := s.client
+	body, err := json.Marshal(map[string]string{"name": s.username, "password": s.password})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"_session", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mediaTypeJSON)
+	req.Header.Set("Accept", mediaTypeJSON)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error opening session: %w", err)
+	}
+	defer drainAndClose(resp.Body)
+
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, responseError("error opening session", resp.StatusCode, respBody)
+	}
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == sessionCookieName {
+			return cookie, nil
+		}
+	}
+	return nil, fmt.Errorf("error opening session: no %s cookie in response", sessionCookieName)
+}
+
+// WithJWTAuth authenticates with a static JSON Web Token, sent as a bearer token in the Authorization header,
+// for servers using the JWT authentication handler. The credentials passed to GetInstance are ignored.
+func WithJWTAuth(token string) Option {
+	return WithTokenProvider(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// WithTokenProvider authenticates with a bearer token obtained from provider, which is called before every request
+// so that it can hand out a refreshed token when the previous one is about to expire. Providers that are expensive
+// to call should cache their token. The credentials passed to GetInstance are ignored.
+//
+// Example:
+//
+//	cs := couchdb.GetInstance("https://couch.example.com", "", "", couchdb.WithTokenProvider(
+//	    func(ctx context.Context) (string, error) {
+//	        return tokenSource.Token(ctx)
+//	    },
+//	))
+func WithTokenProvider(provider func(ctx context.Context) (string, error)) Option {
+	return func(c *CustomHTTPClient) {
+		c.auth = tokenAuth(provider)
+	}
+}
+
+// tokenAuth authenticates requests with a bearer token obtained from a provider.
+type tokenAuth func(ctx context.Context) (string, error)
+
+func (t tokenAuth) Authenticate(req *http.Request) error {
+	token, err := t(req.Context())
+	if err != nil {
+		return fmt.Errorf("error getting auth token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// WithProxyAuth authenticates through the proxy authentication handler, for servers sitting behind a gateway
+// that has already authenticated the user. Every request carries the user name and roles in the
+// X-Auth-CouchDB-UserName and X-Auth-CouchDB-Roles headers and, if secret is not empty, an X-Auth-CouchDB-Token
+// header holding the hex-encoded HMAC-SHA1 of the user name keyed with the secret configured on the server.
+// The credentials passed to GetInstance are ignored.
+func WithProxyAuth(username string, roles []string, secret string) Option {
+	auth := &proxyAuth{username: username, roles: strings.Join(roles, ",")}
+	if secret != "" {
+		mac := hmac.New(sha1.New, []byte(secret))
+		mac.Write([]byte(username))
+		auth.token = hex.EncodeToString(mac.Sum(nil))
+	}
+	return func(c *CustomHTTPClient) {
+		c.auth = auth
+	}
+}
+
+// proxyAuth authenticates requests with the proxy authentication headers.
+type proxyAuth struct {
+	username string
+	roles    string // Comma-separated roles
+	token    string // Empty if the server doesn't require a token
+}
+
+func (p *proxyAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("X-Auth-CouchDB-UserName", p.username)
+	req.Header.Set("X-Auth-CouchDB-Roles", p.roles)
+	if p.token != "" {
+		req.Header.Set("X-Auth-CouchDB-Token", p.token)
+	}
+	return nil
+}
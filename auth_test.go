@@ -0,0 +1,333 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSessionServer returns a server that opens sessions for jane:s3cret and only accepts requests
+// carrying a session cookie, counting the logins.
+func newSessionServer(t *testing.T, logins *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_session" {
+			var creds map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&creds)
+			if creds["name"] != "jane" || creds["password"] != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			atomic.AddInt32(logins, 1)
+			http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: "session-1"})
+			_, _ = w.Write([]byte(`{"ok":true}`))
+			return
+		}
+
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Errorf("Unexpected basic credentials in %s", r.URL)
+		}
+		if cookie, err := r.Cookie(sessionCookieName); err != nil || cookie.Value != "session-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+}
+
+func TestCookieAuth(t *testing.T) {
+	var logins int32
+	server := newSessionServer(t, &logins)
+	defer server.Close()
+
+	client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)
+	WithCookieAuth()(client)
+	session := client.auth.(*cookieSession)
+	session.username, session.password = "jane", "s3cret"
+
+	for i := 0; i < 3; i++ {
+		code, _, err := client.Get(context.Background(), "test")
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("Unexpected response: %d, %v", code, err)
+		}
+	}
+	if logins != 1 {
+		t.Errorf("Expected a single login, got %d", logins)
+	}
+}
+
+func TestCookieAuthInvalidCredentials(t *testing.T) {
+	var logins int32
+	server := newSessionServer(t, &logins)
+	defer server.Close()
+
+	client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)
+	WithCookieAuth()(client)
+	session := client.auth.(*cookieSession)
+	session.username, session.password = "jane", "wrong"
+
+	if _, _, err := client.Get(context.Background(), "test"); err == nil {
+		t.Errorf("Expected an error when the session cannot be opened")
+	}
+}
+
+func TestCookieAuthRenewal(t *testing.T) {
+	var logins int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_session":
+			n := atomic.AddInt32(&logins, 1)
+			http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: fmt.Sprintf("session-%d", n)})
+		case "/refresh":
+			// Active sessions get a refreshed cookie, which must replace the current one.
+			http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: "session-refreshed", MaxAge: 600})
+		default:
+			cookie, err := r.Cookie(sessionCookieName)
+			if err != nil || cookie.Value == "session-1" {
+				w.WriteHeader(http.StatusUnauthorized) // The first session has expired server-side
+				return
+			}
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)
+	WithCookieAuth()(client)
+	session := client.auth.(*cookieSession)
+
+	code, _, err := client.Get(context.Background(), "test")
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("Expected the request to succeed after renewing the session, got %d, %v", code, err)
+	}
+	if logins != 2 {
+		t.Errorf("Expected 2 logins, got %d", logins)
+	}
+
+	if _, _, err := client.Get(context.Background(), "refresh"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if session.cookie.Value != "session-refreshed" || session.expires.IsZero() {
+		t.Errorf("Expected the refreshed cookie to be stored, got %v expiring at %v", session.cookie, session.expires)
+	}
+
+	// A cookie about to expire is renewed before being sent.
+	session.expires = time.Now().Add(sessionRenewMargin / 2)
+	if _, _, err := client.Get(context.Background(), "test"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if logins != 3 {
+		t.Errorf("Expected the session to be renewed before expiry, got %d logins", logins)
+	}
+}
+
+func TestTokenAuth(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name      string
+		option    Option
+		expected  string
+		expectErr bool
+	}{
+		{name: "static token", option: WithJWTAuth("abc.def.ghi"), expected: "Bearer abc.def.ghi"},
+		{
+			name: "token provider",
+			option: WithTokenProvider(func(context.Context) (string, error) {
+				return "fresh", nil
+			}),
+			expected: "Bearer fresh",
+		},
+		{
+			name: "failing provider",
+			option: WithTokenProvider(func(context.Context) (string, error) {
+				return "", fmt.Errorf("no token")
+			}),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			authorization = ""
+			client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)
+			tc.option(client)
+
+			_, _, err := client.Get(context.Background(), "test")
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("Expected error: %v, got %v", tc.expectErr, err)
+			}
+			if authorization != tc.expected {
+				t.Errorf("Expected Authorization %q, got %q", tc.expected, authorization)
+			}
+		})
+	}
+}
+
+func TestProxyAuth(t *testing.T) {
+	testCases := []struct {
+		name     string
+		secret   string
+		expected http.Header
+	}{
+		{
+			name:   "with secret",
+			secret: "92de07df7e7a3fe14808cef90a7cc0d91",
+			expected: http.Header{
+				"X-Auth-Couchdb-Username": {"jane"},
+				"X-Auth-Couchdb-Roles":    {"users,admins"},
+				// hex(HMAC-SHA1(secret, "jane"))
+				"X-Auth-Couchdb-Token": {"390df92dd2f195c73cd6b06b6b4f12e80fabfdd6"},
+			},
+		},
+		{
+			name:   "without secret",
+			secret: "",
+			expected: http.Header{
+				"X-Auth-Couchdb-Username": {"jane"},
+				"X-Auth-Couchdb-Roles":    {"users,admins"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewCustomHTTPClient("http://localhost/", 1, time.Millisecond, time.Second)
+			WithProxyAuth("jane", []string{"users", "admins"}, tc.secret)(client)
+
+			req, err := client.newHTTPRequest(context.Background(), &request{method: "GET", endpoint: "test"}, nil, "")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for name := range tc.expected {
+				if got := req.Header.Get(name); got != tc.expected.Get(name) {
+					t.Errorf("Expected %s %q, got %q", name, tc.expected.Get(name), got)
+				}
+			}
+			if tc.secret == "" && req.Header.Get("X-Auth-CouchDB-Token") != "" {
+				t.Errorf("Expected no token header without a secret")
+			}
+		})
+	}
+}
+
+// rotatingAuth is a custom Authenticator whose key changes on every refresh.
+type rotatingAuth struct {
+	key       int
+	refreshes int
+}
+
+func (a *rotatingAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("X-Api-Key", fmt.Sprintf("key-%d", a.key))
+	return nil
+}
+
+func (a *rotatingAuth) Refresh(context.Context) error {
+	a.key++
+	a.refreshes++
+	return nil
+}
+
+func TestCustomAuthenticator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name              string
+		startKey          int
+		expectedCode      int
+		expectedRefreshes int
+	}{
+		{name: "valid credentials", startKey: 1, expectedCode: http.StatusOK, expectedRefreshes: 0},
+		{name: "refreshed credentials", startKey: 0, expectedCode: http.StatusOK, expectedRefreshes: 1},
+		{name: "refreshed only once", startKey: -1, expectedCode: http.StatusUnauthorized, expectedRefreshes: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth := &rotatingAuth{key: tc.startKey}
+			client := NewCustomHTTPClient(server.URL+"/", 3, time.Millisecond, time.Second)
+			WithAuthenticator(auth)(client)
+
+			code, _, err := client.Get(context.Background(), "test")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if code != tc.expectedCode || auth.refreshes != tc.expectedRefreshes {
+				t.Errorf("Expected %d after %d refreshes, got %d after %d", tc.expectedCode, tc.expectedRefreshes, code, auth.refreshes)
+			}
+		})
+	}
+}
+
+func TestCustomAuthenticatorStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name              string
+		startKey          int
+		expectedCode      int
+		expectedRefreshes int
+	}{
+		{name: "valid credentials", startKey: 1, expectedCode: http.StatusOK, expectedRefreshes: 0},
+		{name: "refreshed credentials", startKey: 0, expectedCode: http.StatusOK, expectedRefreshes: 1},
+		{name: "refreshed only once", startKey: -1, expectedCode: http.StatusUnauthorized, expectedRefreshes: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth := &rotatingAuth{key: tc.startKey}
+			client := NewCustomHTTPClient(server.URL+"/", 3, time.Millisecond, time.Second)
+			WithAuthenticator(auth)(client)
+
+			resp, err := client.stream(context.Background(), &request{method: "GET", endpoint: "test/_changes"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tc.expectedCode || auth.refreshes != tc.expectedRefreshes {
+				t.Errorf("Expected %d after %d refreshes, got %d after %d", tc.expectedCode, tc.expectedRefreshes, resp.StatusCode, auth.refreshes)
+			}
+		})
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	client := NewCustomHTTPClient("http://localhost/", 1, time.Millisecond, time.Second)
+	WithBasicAuth()(client)
+	auth := client.auth.(*basicAuth)
+	auth.username, auth.password = "jane", "s3cret"
+
+	req, err := client.newHTTPRequest(context.Background(), &request{method: "GET", endpoint: "test"}, nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if username, password, ok := req.BasicAuth(); !ok || username != "jane" || password != "s3cret" {
+		t.Errorf("Expected basic credentials in the Authorization header, got %q", req.Header.Get("Authorization"))
+	}
+	if req.URL.User != nil {
+		t.Errorf("Expected no credentials in the URL, got %s", req.URL)
+	}
+}
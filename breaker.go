@@ -0,0 +1,76 @@
+package couchdb
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen once threshold consecutive attempts have failed
+// with a transport error or a 5xx response, instead of spending every retry and timeout of every request on a server
+// that is down. After coolDown, a single request is let through to probe the server: if it succeeds, the circuit
+// closes again; otherwise, it stays open for another coolDown.
+//
+// The breaker is shared by every database handle obtained from the service.
+//
+// Example:
+//
+//	cs, err := couchdb.New(url, couchdb.WithCircuitBreaker(5, 30*time.Second))
+//	...
+//	if err := db.GetDoc(ctx, id, &doc); errors.Is(err, couchdb.ErrCircuitOpen) {
+//	    return cachedDoc, nil
+//	}
+func WithCircuitBreaker(threshold int, coolDown time.Duration) Option {
+	return func(c *CustomHTTPClient) {
+		c.breaker = &circuitBreaker{threshold: max(threshold, 1), coolDown: coolDown}
+	}
+}
+
+// circuitBreaker counts the consecutive failed attempts of a client and decides whether to let requests through.
+type circuitBreaker struct {
+	threshold int
+	coolDown  time.Duration
+
+	mu       sync.Mutex
+	failures int       // Consecutive failed attempts
+	openedAt time.Time // When the circuit last opened, or a probe failed
+	probing  bool      // Whether a probe is in flight while the circuit is open
+}
+
+// allow reports whether an attempt may be sent. Once the cool-down is over, it lets a single probe through at a time.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.coolDown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record updates the breaker with the outcome of an attempt.
+func (b *circuitBreaker) record(statusCode int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil && statusCode < http.StatusInternalServerError {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// cancel releases a probe whose attempt was interrupted by its context, without counting it either way.
+func (b *circuitBreaker) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
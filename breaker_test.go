@@ -0,0 +1,51 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var status int
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second, WithCircuitBreaker(2, 50*time.Millisecond))
+
+	steps := []struct {
+		name             string
+		status           int
+		wait             time.Duration
+		expectedErr      error
+		expectedAttempts int
+	}{
+		{name: "client errors don't count", status: http.StatusNotFound, expectedAttempts: 1},
+		{name: "first server error", status: http.StatusInternalServerError, expectedAttempts: 2},
+		{name: "second server error opens the circuit", status: http.StatusInternalServerError, expectedAttempts: 3},
+		{name: "open circuit fails fast", status: http.StatusOK, expectedErr: ErrCircuitOpen, expectedAttempts: 3},
+		{name: "failed probe keeps the circuit open", status: http.StatusServiceUnavailable, wait: 60 * time.Millisecond, expectedAttempts: 4},
+		{name: "circuit open after failed probe", status: http.StatusOK, expectedErr: ErrCircuitOpen, expectedAttempts: 4},
+		{name: "successful probe closes the circuit", status: http.StatusOK, wait: 60 * time.Millisecond, expectedAttempts: 5},
+		{name: "closed circuit", status: http.StatusOK, expectedAttempts: 6},
+	}
+
+	for _, step := range steps {
+		time.Sleep(step.wait)
+		status = step.status
+		_, _, err := client.Get(context.Background(), "test")
+		if !errors.Is(err, step.expectedErr) {
+			t.Fatalf("%s: expected error %v, got %v", step.name, step.expectedErr, err)
+		}
+		if attempts != step.expectedAttempts {
+			t.Fatalf("%s: expected %d attempts, got %d", step.name, step.expectedAttempts, attempts)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// BulkDocResult is the outcome of writing a single document through BulkDocs.
+// Either Ok and Rev are set, or Error and Reason describe why the document was rejected.
+type BulkDocResult struct {
+	ID     string `json:"id"`
+	Rev    string `json:"rev,omitempty"`
+	Ok     bool   `json:"ok,omitempty"`
+	Error  string `json:"error,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// BulkDocs creates, updates or deletes several documents in a single request to the _bulk_docs endpoint.
+//
+// Documents without an "_id" are created with a server-generated ID, documents with "_id" and "_rev" are updated,
+// and documents that also set "_deleted": true are deleted. Writes are not atomic: each document succeeds or fails
+// on its own, so a rejected document (e.g. because of a conflict) is reported in its result rather than as an error.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - docs: The documents to write. Each one can be of any type that marshals to a JSON object.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - []BulkDocResult: One result per document, in the same order as docs.
+//   - error: An error if the request as a whole failed.
+//
+// Example:
+//
+//	results, err := db.BulkDocs(ctx, []any{
+//	    map[string]any{"name": "John Doe"},
+//	    map[string]any{"_id": "jane", "name": "Jane Doe"},
+//	})
+//	if err != nil {
+//	    log.Fatalf("Error writing documents: %v", err)
+//	}
+//	for _, result := range results {
+//	    if result.Error != "" {
+//	        log.Printf("Document %s rejected: %s - %s", result.ID, result.Error, result.Reason)
+//	    }
+//	}
+func (db *Database) BulkDocs(ctx context.Context, docs []any, opts ...RequestOption) ([]BulkDocResult, error) {
+	body := map[string]any{"docs": docs}
+
+	respCode, respBody, err := db.httpClient.Post(ctx, fmt.Sprintf("%s/_bulk_docs", db.dbName), body, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error writing bulk docs: %w", err)
+	}
+
+	if respCode != 200 && respCode != 201 && respCode != 202 {
+		return nil, responseError("error writing bulk docs", respCode, respBody)
+	}
+
+	var results []BulkDocResult
+	err = json.Unmarshal(respBody, &results)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling bulk docs response: %w", err)
+	}
+
+	return results, nil
+}
+
+// EnsureFullCommit asks the server to flush to disk the writes it has acknowledged but not committed yet,
+// such as those made with the batch=ok query parameter (see WithQueryParam).
+// Recent CouchDB versions commit every write before acknowledging it, so there is nothing left to flush.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - string: The instance start time of the database. If it differs from the one returned by a previous call,
+//     the server restarted in between and batched writes acknowledged before the restart may have been lost.
+//   - error: An error, if any, encountered while requesting the commit.
+//
+// Example:
+//
+//	for _, event := range events {
+//	    if _, err := db.CreateDoc(ctx, event, couchdb.WithQueryParam("batch", "ok")); err != nil {
+//	        log.Fatalf("Error writing event: %v", err)
+//	    }
+//	}
+//	if _, err := db.EnsureFullCommit(ctx); err != nil {
+//	    log.Fatalf("Error committing events: %v", err)
+//	}
+func (db *Database) EnsureFullCommit(ctx context.Context, opts ...RequestOption) (string, error) {
+	respCode, respBody, err := db.httpClient.Post(ctx, fmt.Sprintf("%s/_ensure_full_commit", db.dbName), nil, opts...)
+	if err != nil {
+		return "", fmt.Errorf("error ensuring full commit: %w", err)
+	}
+
+	if respCode != 201 {
+		return "", responseError("error ensuring full commit", respCode, respBody)
+	}
+
+	var commitResponse struct {
+		Ok                bool   `json:"ok"`
+		InstanceStartTime string `json:"instance_start_time"`
+	}
+	err = json.Unmarshal(respBody, &commitResponse)
+	if err != nil {
+		return "", fmt.Errorf("error unmarshalling ensure full commit response: %w", err)
+	}
+
+	return commitResponse.InstanceStartTime, nil
+}
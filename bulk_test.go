@@ -0,0 +1,111 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBulkDocs(t *testing.T) {
+	docs := []any{
+		map[string]any{"name": "John Doe"},
+		map[string]any{"_id": "jane", "_rev": "1-a", "name": "Jane Doe"},
+		map[string]any{"_id": "old", "_rev": "2-b", "_deleted": true},
+	}
+
+	testCases := []struct {
+		name            string
+		statusCode      int
+		body            string
+		expectedResults []BulkDocResult
+		expectedError   error
+	}{
+		{
+			name:       "mixed results",
+			statusCode: 201,
+			body:       `[{"ok":true,"id":"c0ffee","rev":"1-x"},{"id":"jane","error":"conflict","reason":"Document update conflict."},{"ok":true,"id":"old","rev":"3-y"}]`,
+			expectedResults: []BulkDocResult{
+				{ID: "c0ffee", Rev: "1-x", Ok: true},
+				{ID: "jane", Error: "conflict", Reason: "Document update conflict."},
+				{ID: "old", Rev: "3-y", Ok: true},
+			},
+		},
+		{name: "invalid request", statusCode: 400, body: `{"error":"bad_request","reason":"POST body must include docs field."}`, expectedError: ErrBadRequest},
+		{name: "missing database", statusCode: 404, body: `{"error":"not_found","reason":"Database does not exist."}`, expectedError: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/test/_bulk_docs" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+
+				var body map[string]json.RawMessage
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("Unexpected request body: %v", err)
+				}
+				if _, ok := body["new_edits"]; ok {
+					t.Errorf("Expected new_edits to be left to its default, got %s", body["new_edits"])
+				}
+				var sent []map[string]any
+				if err := json.Unmarshal(body["docs"], &sent); err != nil || len(sent) != len(docs) || sent[1]["_rev"] != "1-a" || sent[2]["_deleted"] != true {
+					t.Errorf("Unexpected docs in request body: %s", body["docs"])
+				}
+
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			results, err := db.BulkDocs(context.Background(), docs)
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if !reflect.DeepEqual(results, tc.expectedResults) {
+				t.Errorf("Expected results %+v, got %+v", tc.expectedResults, results)
+			}
+		})
+	}
+}
+
+func TestEnsureFullCommit(t *testing.T) {
+	testCases := []struct {
+		name              string
+		statusCode        int
+		body              string
+		expectedStartTime string
+		expectedError     error
+	}{
+		{name: "commit", statusCode: 201, body: `{"ok":true,"instance_start_time":"1697212345"}`, expectedStartTime: "1697212345"},
+		{name: "missing database", statusCode: 404, body: `{"error":"not_found","reason":"Database does not exist."}`, expectedError: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/test/_ensure_full_commit" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			startTime, err := db.EnsureFullCommit(context.Background())
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if startTime != tc.expectedStartTime {
+				t.Errorf("Expected instance start time %q, got %q", tc.expectedStartTime, startTime)
+			}
+		})
+	}
+}
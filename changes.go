@@ -0,0 +1,419 @@
+package couchdb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultHeartbeat is the heartbeat interval requested for continuous feeds that don't set one.
+	defaultHeartbeat = 30 * time.Second
+	// defaultLongpollTimeout is how long a longpoll request waits for changes when no timeout is set,
+	// matching the CouchDB default.
+	defaultLongpollTimeout = 60 * time.Second
+)
+
+// Seq is a database update sequence.
+// CouchDB 2.0+ uses opaque string sequences while older servers use integers; both are decoded into a Seq,
+// which can be passed back as ChangesOptions.Since to resume a feed.
+type Seq string
+
+// UnmarshalJSON decodes a sequence given either as a JSON string or as a JSON number.
+func (s *Seq) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*s = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		*s = Seq(str)
+		return nil
+	}
+	*s = Seq(data)
+	return nil
+}
+
+// ChangesOptions are the query options of the changes feed.
+type ChangesOptions struct {
+	Since       Seq  // Return changes after this sequence; "now" starts at the current sequence, empty starts at the beginning
+	Limit       int  // Maximum number of changes to return; 0 means no limit
+	IncludeDocs bool // Include the document of each change
+	Descending  bool // Return changes in descending sequence order
+
+	// Heartbeat is the interval at which the server sends an empty line on an idle continuous feed.
+	// ContinuousChanges uses it to detect dead connections and defaults it to 30 seconds.
+	Heartbeat time.Duration
+	// Timeout is how long the server waits for a change before ending a longpoll or continuous feed.
+	// WaitForChanges defaults it to 60 seconds.
+	Timeout time.Duration
+
+	// Filter is the filter function to apply, as "ddoc/name". Use FilterParams to pass it query parameters.
+	Filter       string
+	FilterParams map[string]string
+	// Selector restricts the feed to documents matching a Mango selector (filter=_selector).
+	Selector any
+	// DocIDs restricts the feed to the given documents (filter=_doc_ids).
+	DocIDs []string
+}
+
+// query encodes the options as query parameters of the _changes endpoint.
+func (o ChangesOptions) query() url.Values {
+	values := url.Values{}
+	if o.Since != "" {
+		values.Set("since", string(o.Since))
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.IncludeDocs {
+		values.Set("include_docs", "true")
+	}
+	if o.Descending {
+		values.Set("descending", "true")
+	}
+	if o.Heartbeat > 0 {
+		values.Set("heartbeat", strconv.FormatInt(o.Heartbeat.Milliseconds(), 10))
+	}
+	if o.Timeout > 0 {
+		values.Set("timeout", strconv.FormatInt(o.Timeout.Milliseconds(), 10))
+	}
+	for key, value := range o.FilterParams {
+		values.Set(key, value)
+	}
+	switch {
+	case o.Selector != nil:
+		values.Set("filter", "_selector")
+	case len(o.DocIDs) > 0:
+		values.Set("filter", "_doc_ids")
+	case o.Filter != "":
+		values.Set("filter", o.Filter)
+	}
+	return values
+}
+
+// request builds the request for the _changes endpoint with the given feed type.
+// Selector and document ID filters are POSTed in the body; every other feed is a plain GET.
+func (o ChangesOptions) request(dbName, feed string) *request {
+	values := o.query()
+	values.Set("feed", feed)
+
+	r := &request{
+		method:   "GET",
+		endpoint: withQuery(fmt.Sprintf("%s/_changes", dbName), values),
+	}
+	switch {
+	case o.Selector != nil:
+		r.method = "POST"
+		r.body = map[string]any{"selector": o.Selector}
+	case len(o.DocIDs) > 0:
+		r.method = "POST"
+		r.body = map[string]any{"doc_ids": o.DocIDs}
+	}
+	return r
+}
+
+// Change is a single entry of the changes feed.
+type Change struct {
+	Seq     Seq             `json:"seq"`
+	ID      string          `json:"id"`
+	Changes []ChangeRev     `json:"changes"`
+	Deleted bool            `json:"deleted,omitempty"`
+	Doc     json.RawMessage `json:"doc,omitempty"` // Only set when IncludeDocs is requested
+}
+
+// ChangeRev is a leaf revision reported by a Change.
+type ChangeRev struct {
+	Rev string `json:"rev"`
+}
+
+// ChangesResponse is the result of a changes feed request.
+type ChangesResponse struct {
+	Results []Change `json:"results"`
+	LastSeq Seq      `json:"last_seq"` // Sequence to pass as Since to continue after these results
+	Pending int      `json:"pending"`  // Number of changes left after the returned ones
+}
+
+// Changes returns the changes made to the database, as reported by the _changes endpoint in normal mode.
+//
+// The returned LastSeq can be used as the Since option of the next call to process changes incrementally.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - opts: The options of the changes feed.
+//   - reqOpts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - *ChangesResponse: The changes, the last sequence and the number of pending changes.
+//   - error: An error, if any, encountered while getting the changes.
+//
+// Example:
+//
+//	since := couchdb.Seq("")
+//	for {
+//	    changes, err := db.Changes(ctx, couchdb.ChangesOptions{Since: since, Limit: 100, IncludeDocs: true})
+//	    if err != nil {
+//	        log.Fatalf("Error getting changes: %v", err)
+//	    }
+//	    for _, change := range changes.Results {
+//	        process(change)
+//	    }
+//	    since = changes.LastSeq
+//	    if changes.Pending == 0 {
+//	        break
+//	    }
+//	}
+func (db *Database) Changes(ctx context.Context, opts ChangesOptions, reqOpts ...RequestOption) (*ChangesResponse, error) {
+	return db.changes(ctx, opts, "normal", 0, reqOpts...)
+}
+
+// WaitForChanges blocks until at least one change after opts.Since is available, or until the feed timeout fires,
+// using a longpoll changes feed (feed=longpoll). When the timeout fires the result has no changes, and its LastSeq
+// can be passed as Since to wait again, which makes for cheap polling loops without a continuous connection.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - opts: The options of the changes feed. Timeout defaults to 60 seconds, and the request is allowed
+//     to last that long on top of the client timeout.
+//   - reqOpts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - *ChangesResponse: The available changes, if any, and the sequence to wait from next.
+//   - error: An error, if any, encountered while waiting for changes.
+//
+// Example:
+//
+//	since := couchdb.Seq("now")
+//	for {
+//	    changes, err := db.WaitForChanges(ctx, couchdb.ChangesOptions{Since: since})
+//	    if err != nil {
+//	        log.Fatalf("Error waiting for changes: %v", err)
+//	    }
+//	    for _, change := range changes.Results {
+//	        process(change)
+//	    }
+//	    since = changes.LastSeq
+//	}
+func (db *Database) WaitForChanges(ctx context.Context, opts ChangesOptions, reqOpts ...RequestOption) (*ChangesResponse, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultLongpollTimeout
+	}
+
+	return db.changes(ctx, opts, "longpoll", opts.Timeout+db.httpClient.timeout, reqOpts...)
+}
+
+// changes requests a non-continuous changes feed of the given type.
+// A positive timeout overrides the client timeout for the request.
+func (db *Database) changes(ctx context.Context, opts ChangesOptions, feed string, timeout time.Duration, reqOpts ...RequestOption) (*ChangesResponse, error) {
+	r := opts.request(db.dbName, feed)
+	r.timeout = timeout
+	for _, opt := range reqOpts {
+		opt(r)
+	}
+
+	resp, err := db.httpClient.do(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("error getting changes: %w", err)
+	}
+
+	if resp.statusCode != 200 {
+		return nil, responseError("error getting changes", resp.statusCode, resp.body)
+	}
+
+	var changesResponse ChangesResponse
+	err = json.Unmarshal(resp.body, &changesResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling changes response: %w", err)
+	}
+
+	return &changesResponse, nil
+}
+
+// ChangesFeed is a continuous changes feed started with ContinuousChanges.
+// Changes are delivered on the Events channel, which is closed once the feed stops.
+type ChangesFeed struct {
+	events chan Change
+	done   chan struct{}
+	cancel context.CancelFunc
+	err    error
+}
+
+// Events returns the channel on which changes are delivered, in sequence order.
+// The channel is closed when the feed stops, either because it was closed, its context ended,
+// or it hit an error it cannot recover from (see Err).
+func (f *ChangesFeed) Events() <-chan Change {
+	return f.events
+}
+
+// Err returns the error that stopped the feed, or nil if it was stopped by Close or by its context.
+// It must only be called once the Events channel has been closed.
+func (f *ChangesFeed) Err() error {
+	return f.err
+}
+
+// Close stops the feed and waits for its connection to be released.
+func (f *ChangesFeed) Close() {
+	f.cancel()
+	<-f.done
+}
+
+// ContinuousChanges opens a long-lived continuous changes feed (feed=continuous) and streams its changes
+// on a channel.
+//
+// The feed honors the heartbeat: if the server stays silent for twice the heartbeat interval, the connection
+// is considered dead. Dead connections, transport errors and server errors are recovered from by reconnecting,
+// resuming from the sequence of the last delivered change, with waits between failed attempts that grow as set
+// by WithBackoff. Client errors (4xx), such as a deleted database, stop the feed and are reported by Err.
+//
+// Parameters:
+//   - ctx: The context controlling the lifetime of the feed.
+//   - opts: The options of the changes feed. Limit and Descending do not apply to continuous feeds.
+//   - reqOpts: Optional per-request options, applied to every connection of the feed.
+//
+// Returns:
+//   - *ChangesFeed: The running feed, which must be stopped with Close when no longer needed.
+//   - error: An error if the initial connection fails.
+//
+// Example:
+//
+//	feed, err := db.ContinuousChanges(ctx, couchdb.ChangesOptions{Since: "now", IncludeDocs: true})
+//	if err != nil {
+//	    log.Fatalf("Error opening changes feed: %v", err)
+//	}
+//	defer feed.Close()
+//	for change := range feed.Events() {
+//	    process(change)
+//	}
+//	if err := feed.Err(); err != nil {
+//	    log.Printf("Changes feed stopped: %v", err)
+//	}
+func (db *Database) ContinuousChanges(ctx context.Context, opts ChangesOptions, reqOpts ...RequestOption) (*ChangesFeed, error) {
+	if opts.Heartbeat <= 0 {
+		opts.Heartbeat = defaultHeartbeat
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	body, _, err := db.openChangesStream(ctx, opts, reqOpts)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	feed := &ChangesFeed{
+		events: make(chan Change),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	go feed.run(ctx, db, opts, reqOpts, body)
+
+	return feed, nil
+}
+
+// openChangesStream opens a continuous changes feed, returning its unread body.
+// On failure it also returns the response status code, which is 0 for transport errors.
+func (db *Database) openChangesStream(ctx context.Context, opts ChangesOptions, reqOpts []RequestOption) (io.ReadCloser, int, error) {
+	r := opts.request(db.dbName, "continuous")
+	for _, opt := range reqOpts {
+		opt(r)
+	}
+
+	resp, err := db.httpClient.stream(ctx, r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error opening changes feed: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, resp.StatusCode, responseError("error opening changes feed", resp.StatusCode, respBody)
+	}
+
+	return resp.Body, resp.StatusCode, nil
+}
+
+// run delivers the changes read from body and keeps reconnecting until the feed is stopped.
+func (f *ChangesFeed) run(ctx context.Context, db *Database, opts ChangesOptions, reqOpts []RequestOption, body io.ReadCloser) {
+	defer close(f.done)
+	defer close(f.events)
+
+	for {
+		f.consume(ctx, body, &opts)
+		if ctx.Err() != nil {
+			return
+		}
+
+		for attempt := 1; ; attempt++ {
+			if sleepContext(ctx, db.httpClient.backoff.wait(db.httpClient.retryWait, attempt)) != nil {
+				return
+			}
+
+			var code int
+			var err error
+			body, code, err = db.openChangesStream(ctx, opts, reqOpts)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if code >= 400 && code < 500 {
+				f.err = err
+				return
+			}
+		}
+	}
+}
+
+// consume reads changes from a single connection until it ends, advancing opts.Since as changes are delivered.
+// A connection that stays silent for twice the heartbeat interval is abandoned.
+func (f *ChangesFeed) consume(ctx context.Context, body io.ReadCloser, opts *ChangesOptions) {
+	defer body.Close()
+
+	watchdog := time.AfterFunc(2*opts.Heartbeat, func() {
+		body.Close()
+	})
+	defer watchdog.Stop()
+
+	reader := bufio.NewReader(body)
+	for {
+		line, err := reader.ReadBytes('\n')
+		watchdog.Reset(2 * opts.Heartbeat)
+
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			var entry struct {
+				Change
+				LastSeq *Seq `json:"last_seq"`
+			}
+			if json.Unmarshal(line, &entry) == nil {
+				if entry.LastSeq != nil {
+					opts.Since = *entry.LastSeq
+					return
+				}
+
+				// A slow consumer must not be mistaken for a silent server.
+				watchdog.Stop()
+				select {
+				case f.events <- entry.Change:
+					opts.Since = entry.Seq
+				case <-ctx.Done():
+					return
+				}
+				watchdog.Reset(2 * opts.Heartbeat)
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
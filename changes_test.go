@@ -0,0 +1,311 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSeqUnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected Seq
+	}{
+		{`"12-g1AAAAB"`, "12-g1AAAAB"},
+		{`42`, "42"},
+		{`null`, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			var seq Seq
+			if err := json.Unmarshal([]byte(tc.input), &seq); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if seq != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, seq)
+			}
+		})
+	}
+}
+
+func TestChangesOptionsQuery(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     ChangesOptions
+		expected string
+	}{
+		{name: "no options", opts: ChangesOptions{}, expected: ""},
+		{name: "since now", opts: ChangesOptions{Since: "now"}, expected: "since=now"},
+		{
+			name:     "all options",
+			opts:     ChangesOptions{Since: "3-abc", Limit: 10, IncludeDocs: true, Descending: true},
+			expected: "descending=true&include_docs=true&limit=10&since=3-abc",
+		},
+		{
+			name:     "filter function with parameters",
+			opts:     ChangesOptions{Filter: "app/by_type", FilterParams: map[string]string{"type": "order"}},
+			expected: "filter=app%2Fby_type&type=order",
+		},
+		{name: "selector filter", opts: ChangesOptions{Selector: map[string]any{"type": "order"}}, expected: "filter=_selector"},
+		{name: "doc IDs filter", opts: ChangesOptions{DocIDs: []string{"a", "b"}}, expected: "filter=_doc_ids"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.opts.query().Encode(); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestChangesOptionsRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		opts           ChangesOptions
+		expectedMethod string
+		expectedBody   string
+	}{
+		{name: "unfiltered", opts: ChangesOptions{}, expectedMethod: "GET", expectedBody: "null"},
+		{name: "filter function", opts: ChangesOptions{Filter: "app/by_type"}, expectedMethod: "GET", expectedBody: "null"},
+		{
+			name:           "selector",
+			opts:           ChangesOptions{Selector: map[string]any{"type": "order"}},
+			expectedMethod: "POST",
+			expectedBody:   `{"selector":{"type":"order"}}`,
+		},
+		{
+			name:           "doc IDs",
+			opts:           ChangesOptions{DocIDs: []string{"a", "b"}},
+			expectedMethod: "POST",
+			expectedBody:   `{"doc_ids":["a","b"]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.opts.request("db", "longpoll")
+			if r.method != tc.expectedMethod {
+				t.Errorf("Expected method %s, got %s", tc.expectedMethod, r.method)
+			}
+			body, _ := json.Marshal(r.body)
+			if string(body) != tc.expectedBody {
+				t.Errorf("Expected body %s, got %s", tc.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestContinuousChangesReconnects(t *testing.T) {
+	var mu sync.Mutex
+	var sinces []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("feed") != "continuous" {
+			t.Errorf("Expected continuous feed, got %q", r.URL.Query().Get("feed"))
+		}
+
+		mu.Lock()
+		sinces = append(sinces, r.URL.Query().Get("since"))
+		connection := len(sinces)
+		mu.Unlock()
+
+		switch connection {
+		case 1:
+			// Deliver two changes, then drop the connection.
+			_, _ = w.Write([]byte("{\"seq\":\"1-a\",\"id\":\"doc1\",\"changes\":[{\"rev\":\"1-x\"}]}\n\n"))
+			_, _ = w.Write([]byte("{\"seq\":\"2-b\",\"id\":\"doc2\",\"changes\":[{\"rev\":\"1-y\"}]}\n"))
+		default:
+			_, _ = w.Write([]byte("{\"seq\":\"3-c\",\"id\":\"doc3\",\"changes\":[{\"rev\":\"1-z\"}]}\n"))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	db := &Database{
+		httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second),
+		dbName:     "test",
+	}
+
+	feed, err := db.ContinuousChanges(context.Background(), ChangesOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var ids []string
+	for change := range feed.Events() {
+		ids = append(ids, change.ID)
+		if len(ids) == 3 {
+			break
+		}
+	}
+	feed.Close()
+
+	if !reflect.DeepEqual(ids, []string{"doc1", "doc2", "doc3"}) {
+		t.Errorf("Unexpected changes: %v", ids)
+	}
+	if feed.Err() != nil {
+		t.Errorf("Unexpected feed error: %v", feed.Err())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sinces) < 2 || sinces[0] != "" || sinces[1] != "2-b" {
+		t.Errorf("Expected to reconnect from the last delivered sequence, got since values %v", sinces)
+	}
+}
+
+func TestContinuousChangesStopsOnClientError(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			return // Empty feed: the connection ends right away
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"not_found","reason":"Database does not exist."}`))
+	}))
+	defer server.Close()
+
+	db := &Database{
+		httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second),
+		dbName:     "test",
+	}
+
+	feed, err := db.ContinuousChanges(context.Background(), ChangesOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for range feed.Events() {
+		t.Errorf("Unexpected change")
+	}
+	if feed.Err() == nil {
+		t.Errorf("Expected the feed to stop with an error")
+	}
+	feed.Close()
+}
+
+func TestContinuousChangesReconnectBackoff(t *testing.T) {
+	var mu sync.Mutex
+	var connections []time.Time
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connections = append(connections, time.Now())
+		connection := len(connections)
+		mu.Unlock()
+
+		switch {
+		case connection == 1:
+			return // Empty feed: the connection ends right away
+		case connection <= 4:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte("{\"seq\":\"1-a\",\"id\":\"doc1\",\"changes\":[{\"rev\":\"1-x\"}]}\n"))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second,
+		WithBackoff(Backoff{Initial: 10 * time.Millisecond, Multiplier: 2}))
+	db := &Database{httpClient: client, dbName: "test"}
+
+	feed, err := db.ContinuousChanges(context.Background(), ChangesOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if change := <-feed.Events(); change.ID != "doc1" {
+		t.Errorf("Expected doc1 after reconnecting, got %q", change.ID)
+	}
+	feed.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(connections) != 5 {
+		t.Fatalf("Expected 5 connections, got %d", len(connections))
+	}
+	// Failed reconnections wait 10ms, 20ms, 40ms and 80ms.
+	for i, expected := range []time.Duration{10, 20, 40, 80} {
+		if gap := connections[i+1].Sub(connections[i]); gap < expected*time.Millisecond {
+			t.Errorf("Expected reconnection %d to wait at least %v, waited %v", i+1, expected*time.Millisecond, gap)
+		}
+	}
+}
+
+func TestWaitForChanges(t *testing.T) {
+	testCases := []struct {
+		name            string
+		opts            ChangesOptions
+		expectedTimeout string
+		delay           time.Duration
+		body            string
+		expectedIDs     []string
+		expectedLastSeq Seq
+	}{
+		{
+			name:            "changes available",
+			opts:            ChangesOptions{Since: "5-a", Timeout: 200 * time.Millisecond},
+			expectedTimeout: "200",
+			body:            `{"results":[{"seq":"6-b","id":"doc1","changes":[{"rev":"2-x"}]}],"last_seq":"6-b","pending":0}`,
+			expectedIDs:     []string{"doc1"},
+			expectedLastSeq: "6-b",
+		},
+		{
+			name:            "default timeout",
+			opts:            ChangesOptions{Since: "5-a"},
+			expectedTimeout: "60000",
+			body:            `{"results":[{"seq":"6-b","id":"doc1","changes":[{"rev":"2-x"}]}],"last_seq":"6-b","pending":0}`,
+			expectedIDs:     []string{"doc1"},
+			expectedLastSeq: "6-b",
+		},
+		{
+			// The server only answers once the feed timeout fires, after the client timeout has elapsed.
+			name:            "feed timeout",
+			opts:            ChangesOptions{Since: "5-a", Timeout: 150 * time.Millisecond},
+			expectedTimeout: "150",
+			delay:           150 * time.Millisecond,
+			body:            `{"results":[],"last_seq":"5-a","pending":0}`,
+			expectedLastSeq: "5-a",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				if r.Method != http.MethodGet || r.URL.Path != "/test/_changes" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if query.Get("feed") != "longpoll" || query.Get("since") != string(tc.opts.Since) || query.Get("timeout") != tc.expectedTimeout {
+					t.Errorf("Unexpected query %v", query)
+				}
+				time.Sleep(tc.delay)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, 50*time.Millisecond), dbName: "test"}
+			changes, err := db.WaitForChanges(context.Background(), tc.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var ids []string
+			for _, change := range changes.Results {
+				ids = append(ids, change.ID)
+			}
+			if !reflect.DeepEqual(ids, tc.expectedIDs) || changes.LastSeq != tc.expectedLastSeq {
+				t.Errorf("Expected changes %v up to %q, got %v up to %q", tc.expectedIDs, tc.expectedLastSeq, ids, changes.LastSeq)
+			}
+		})
+	}
+}
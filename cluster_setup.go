@@ -0,0 +1,116 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Actions of ClusterSetup.
+const (
+	ClusterSetupEnableSingleNode = "enable_single_node" // Configure a single-node install and create its system databases
+	ClusterSetupEnableCluster    = "enable_cluster"     // Configure a node, or a remote node, to join a cluster
+	ClusterSetupAddNode          = "add_node"           // Add a configured node to the cluster of the node receiving the request
+	ClusterSetupFinishCluster    = "finish_cluster"     // Create the system databases once all nodes are added
+)
+
+// States returned by ClusterSetupState.
+const (
+	ClusterDisabled    = "cluster_disabled"
+	ClusterEnabled     = "cluster_enabled"
+	ClusterFinished    = "cluster_finished"
+	SingleNodeDisabled = "single_node_disabled"
+	SingleNodeEnabled  = "single_node_enabled"
+)
+
+// ClusterSetupAction is a step of the setup of a CouchDB install, as described [here](https://docs.couchdb.org/en/stable/setup/cluster.html#the-cluster-setup-api).
+// Which fields are required depends on the Action.
+type ClusterSetupAction struct {
+	Action                string   `json:"action"`                            // One of the ClusterSetup* actions
+	Username              string   `json:"username,omitempty"`                // Admin to create on the node, or to authenticate to the added node with
+	Password              string   `json:"password,omitempty"`                // Password of Username
+	BindAddress           string   `json:"bind_address,omitempty"`            // Address the node listens on, e.g. "0.0.0.0"
+	Port                  int      `json:"port,omitempty"`                    // Port the node listens on, or of the added node
+	NodeCount             int      `json:"node_count,omitempty"`              // Number of nodes of the cluster
+	RemoteNode            string   `json:"remote_node,omitempty"`             // Host of the node to configure, if not the one receiving the request
+	RemoteCurrentUser     string   `json:"remote_current_user,omitempty"`     // Current admin of RemoteNode
+	RemoteCurrentPassword string   `json:"remote_current_password,omitempty"` // Password of RemoteCurrentUser
+	Host                  string   `json:"host,omitempty"`                    // Host of the node to add
+	EnsureDBsExist        []string `json:"ensure_dbs_exist,omitempty"`        // System databases to create; the server default is _users, _replicator and _global_changes
+	SingleNode            bool     `json:"singlenode,omitempty"`              // For ClusterSetupFinishCluster, whether the install is a single node
+}
+
+// ClusterSetup runs a step of the setup of the install, so deployments can bootstrap CouchDB without the web UI.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - action: The step to run.
+//
+// Returns:
+//   - error: An error, if any, encountered while running the step.
+//
+// Example:
+//
+//	err := cs.ClusterSetup(ctx, couchdb.ClusterSetupAction{
+//	    Action:      couchdb.ClusterSetupEnableSingleNode,
+//	    Username:    "admin",
+//	    Password:    "s3cret",
+//	    BindAddress: "0.0.0.0",
+//	    Port:        5984,
+//	})
+//	if err != nil {
+//	    log.Fatalf("Error setting up CouchDB: %v", err)
+//	}
+func (c *CouchService) ClusterSetup(ctx context.Context, action ClusterSetupAction) error {
+	respCode, respBody, err := c.httpClient.Post(ctx, "_cluster_setup", action)
+	if err != nil {
+		return fmt.Errorf("error running cluster setup: %w", err)
+	}
+
+	if respCode != 200 && respCode != 201 {
+		return responseError("error running cluster setup", respCode, respBody)
+	}
+
+	return nil
+}
+
+// ClusterSetupState returns how far the setup of the install went, as one of the ClusterDisabled, ClusterEnabled,
+// ClusterFinished, SingleNodeDisabled and SingleNodeEnabled states.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - ensureDBsExist: The system databases whose existence defines a finished setup; empty for the server default.
+//
+// Returns:
+//   - string: The state of the setup.
+//   - error: An error, if any, encountered while getting the state.
+func (c *CouchService) ClusterSetupState(ctx context.Context, ensureDBsExist []string) (string, error) {
+	values := url.Values{}
+	if len(ensureDBsExist) > 0 {
+		dbs, err := json.Marshal(ensureDBsExist)
+		if err != nil {
+			return "", fmt.Errorf("error encoding ensure_dbs_exist: %w", err)
+		}
+		values.Set("ensure_dbs_exist", string(dbs))
+	}
+
+	respCode, respBody, err := c.httpClient.Get(ctx, withQuery("_cluster_setup", values))
+	if err != nil {
+		return "", fmt.Errorf("error getting cluster setup state: %w", err)
+	}
+
+	if respCode != 200 {
+		return "", responseError("error getting cluster setup state", respCode, respBody)
+	}
+
+	var stateResponse struct {
+		State string `json:"state"`
+	}
+	err = json.Unmarshal(respBody, &stateResponse)
+	if err != nil {
+		return "", fmt.Errorf("error unmarshalling cluster setup state: %w", err)
+	}
+
+	return stateResponse.State, nil
+}
@@ -0,0 +1,109 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClusterSetup(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/_cluster_setup" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		received = nil
+		json.NewDecoder(r.Body).Decode(&received)
+		if received["action"] == "bogus" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad_request","reason":"Invalid action"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	testCases := []struct {
+		name         string
+		action       ClusterSetupAction
+		expectedBody map[string]any
+		err          error
+	}{
+		{
+			name:   "enable single node",
+			action: ClusterSetupAction{Action: ClusterSetupEnableSingleNode, Username: "admin", Password: "s3cret", BindAddress: "0.0.0.0", Port: 5984},
+			expectedBody: map[string]any{
+				"action": "enable_single_node", "username": "admin", "password": "s3cret", "bind_address": "0.0.0.0", "port": float64(5984),
+			},
+		},
+		{
+			name:         "add node",
+			action:       ClusterSetupAction{Action: ClusterSetupAddNode, Host: "10.0.0.2", Port: 5984, Username: "admin", Password: "s3cret"},
+			expectedBody: map[string]any{"action": "add_node", "host": "10.0.0.2", "port": float64(5984), "username": "admin", "password": "s3cret"},
+		},
+		{
+			name:         "finish cluster",
+			action:       ClusterSetupAction{Action: ClusterSetupFinishCluster},
+			expectedBody: map[string]any{"action": "finish_cluster"},
+		},
+		{
+			name:         "invalid action",
+			action:       ClusterSetupAction{Action: "bogus"},
+			expectedBody: map[string]any{"action": "bogus"},
+			err:          ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := cs.ClusterSetup(context.Background(), tc.action); !errors.Is(err, tc.err) {
+				t.Errorf("Expected error %v, got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(received, tc.expectedBody) {
+				t.Errorf("Expected body %v, got %v", tc.expectedBody, received)
+			}
+		})
+	}
+}
+
+func TestClusterSetupState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ensure_dbs_exist") == `["_users"]` {
+			w.Write([]byte(`{"state":"single_node_enabled"}`))
+			return
+		}
+		w.Write([]byte(`{"state":"cluster_disabled"}`))
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	testCases := []struct {
+		name     string
+		dbs      []string
+		expected string
+	}{
+		{name: "default system databases", expected: ClusterDisabled},
+		{name: "custom system databases", dbs: []string{"_users"}, expected: SingleNodeEnabled},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state, err := cs.ClusterSetupState(context.Background(), tc.dbs)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if state != tc.expected {
+				t.Errorf("Expected state %s, got %s", tc.expected, state)
+			}
+		})
+	}
+}
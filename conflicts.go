@@ -0,0 +1,125 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ConflictResolver chooses the content of a document with conflicts from its conflicting revisions,
+// for ResolveConflicts. The revisions are the full documents, the winning one chosen by the server first.
+// The returned winner is written as the new revision of the document, so a resolver that just picks one
+// of the revisions can return it as is.
+type ConflictResolver func(revs []json.RawMessage) (winner any, err error)
+
+// ResolveConflicts resolves the conflicts of a document: it fetches all its conflicting revisions, lets resolver
+// choose the content of the document, and, in a single bulk request, writes it on top of the winning revision while
+// deleting the other ones. The resolver is not called if the document has no conflicts.
+// Like those of BulkDocs, the writes are not atomic, so a failed resolution may have deleted some revisions.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP requests.
+//   - id: The ID of the document.
+//   - resolver: The function choosing the content of the document.
+//   - opts: Optional per-request options, applied to every request made while resolving the conflicts.
+//
+// Returns:
+//   - string: The new revision of the document, or its current one if it had no conflicts.
+//   - error: An error, if any, returned by resolver or encountered while resolving the conflicts.
+//     A *ConflictError is returned if the document changed meanwhile, in which case resolving can be tried again.
+//
+// Example:
+//
+//	// Keep the revision with the latest modification time.
+//	rev, err := db.ResolveConflicts(ctx, "invoice:42", func(revs []json.RawMessage) (any, error) {
+//	    var latest Invoice
+//	    for _, raw := range revs {
+//	        var invoice Invoice
+//	        if err := json.Unmarshal(raw, &invoice); err != nil {
+//	            return nil, err
+//	        }
+//	        if invoice.ModifiedAt.After(latest.ModifiedAt) {
+//	            latest = invoice
+//	        }
+//	    }
+//	    return latest, nil
+//	})
+func (db *Database) ResolveConflicts(ctx context.Context, id string, resolver ConflictResolver, opts ...RequestOption) (string, error) {
+	var current Document
+	if err := db.getDoc(ctx, id, GetDocOptions{Conflicts: true}, &current, opts...); err != nil {
+		return "", err
+	}
+	if len(current.Conflicts) == 0 {
+		return current.Rev, nil
+	}
+
+	leafRevs := append([]string{current.Rev}, current.Conflicts...)
+	openRevs, err := db.GetOpenRevs(ctx, id, leafRevs, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	byRev := make(map[string]json.RawMessage, len(openRevs))
+	for _, openRev := range openRevs {
+		if openRev.Missing != "" {
+			continue
+		}
+		var leaf Document
+		if err := json.Unmarshal(openRev.Ok, &leaf); err != nil {
+			return "", fmt.Errorf("error unmarshalling open rev: %w", err)
+		}
+		byRev[leaf.Rev] = openRev.Ok
+	}
+
+	revs := make([]json.RawMessage, 0, len(leafRevs))
+	for _, rev := range leafRevs {
+		doc, ok := byRev[rev]
+		if !ok {
+			// Another client resolved or updated the document since its conflicts were listed.
+			return "", db.conflictError(ctx, id, &Error{StatusCode: 409, ErrorName: "conflict", Reason: fmt.Sprintf("revision %s no longer exists", rev), action: "error resolving conflicts"})
+		}
+		revs = append(revs, doc)
+	}
+
+	winner, err := resolver(revs)
+	if err != nil {
+		return "", fmt.Errorf("error resolving conflicts: %w", err)
+	}
+
+	encoded, err := json.Marshal(winner)
+	if err != nil {
+		return "", fmt.Errorf("error encoding doc: %w", err)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(encoded, &body); err != nil || body == nil {
+		return "", fmt.Errorf("winner must marshal into a JSON object")
+	}
+	body["_id"] = id
+	body["_rev"] = current.Rev
+	delete(body, "_conflicts")
+
+	docs := []any{body}
+	for _, rev := range current.Conflicts {
+		docs = append(docs, map[string]any{"_id": id, "_rev": rev, "_deleted": true})
+	}
+
+	results, err := db.BulkDocs(ctx, docs, opts...)
+	if err != nil {
+		return "", err
+	}
+	if len(results) != len(docs) {
+		return "", fmt.Errorf("error resolving conflicts: expected %d bulk results, got %d", len(docs), len(results))
+	}
+
+	for _, result := range results {
+		if result.Error == "" {
+			continue
+		}
+		if result.Error == "conflict" {
+			return "", db.conflictError(ctx, id, &Error{StatusCode: 409, ErrorName: result.Error, Reason: result.Reason, action: "error resolving conflicts"})
+		}
+		return "", fmt.Errorf("error resolving conflicts: revision rejected: %s: %s", result.Error, result.Reason)
+	}
+
+	return results[0].Rev, nil
+}
@@ -0,0 +1,143 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestResolveConflicts(t *testing.T) {
+	testCases := []struct {
+		name         string
+		doc          string
+		openRevs     string
+		bulkResults  string
+		expectedRev  string
+		expectedBulk []map[string]any
+		err          error
+	}{
+		{
+			name:        "no conflicts",
+			doc:         `{"_id":"doc1","_rev":"2-c","v":1}`,
+			expectedRev: "2-c",
+		},
+		{
+			name:        "conflicts resolved",
+			doc:         `{"_id":"doc1","_rev":"2-c","v":1,"_conflicts":["2-b"]}`,
+			openRevs:    `[{"ok":{"_id":"doc1","_rev":"2-b","v":5}},{"ok":{"_id":"doc1","_rev":"2-c","v":1}}]`,
+			bulkResults: `[{"id":"doc1","ok":true,"rev":"3-d"},{"id":"doc1","ok":true,"rev":"3-e"}]`,
+			expectedRev: "3-d",
+			expectedBulk: []map[string]any{
+				{"_id": "doc1", "_rev": "2-c", "v": float64(6)},
+				{"_id": "doc1", "_rev": "2-b", "_deleted": true},
+			},
+		},
+		{
+			name:     "conflict resolved meanwhile",
+			doc:      `{"_id":"doc1","_rev":"2-c","v":1,"_conflicts":["2-b"]}`,
+			openRevs: `[{"ok":{"_id":"doc1","_rev":"2-c","v":1}},{"missing":"2-b"}]`,
+			err:      ErrConflict,
+		},
+		{
+			name:        "winner rejected",
+			doc:         `{"_id":"doc1","_rev":"2-c","v":1,"_conflicts":["2-b"]}`,
+			openRevs:    `[{"ok":{"_id":"doc1","_rev":"2-c","v":1}},{"ok":{"_id":"doc1","_rev":"2-b","v":5}}]`,
+			bulkResults: `[{"id":"doc1","error":"conflict","reason":"Document update conflict."},{"id":"doc1","ok":true,"rev":"3-e"}]`,
+			err:         ErrConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var bulk struct {
+				Docs []map[string]any `json:"docs"`
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/test/_bulk_docs":
+					json.NewDecoder(r.Body).Decode(&bulk)
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(tc.bulkResults))
+				case r.Method == http.MethodHead:
+					w.Header().Set("ETag", `"3-f"`)
+				case r.URL.Query().Get("open_revs") != "":
+					w.Write([]byte(tc.openRevs))
+				default:
+					w.Write([]byte(tc.doc))
+				}
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+			called := false
+			rev, err := db.ResolveConflicts(context.Background(), "doc1", func(revs []json.RawMessage) (any, error) {
+				called = true
+				// Sum the values of all revisions, which must be passed winner first.
+				sum := 0
+				for i, raw := range revs {
+					var doc struct {
+						Rev string `json:"_rev"`
+						V   int    `json:"v"`
+					}
+					if err := json.Unmarshal(raw, &doc); err != nil {
+						return nil, err
+					}
+					if i == 0 && doc.Rev != "2-c" {
+						t.Errorf("Expected the winning revision first, got %s", doc.Rev)
+					}
+					sum += doc.V
+				}
+				return map[string]any{"v": sum}, nil
+			})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				var conflict *ConflictError
+				if !errors.As(err, &conflict) || conflict.CurrentRev != "3-f" {
+					t.Errorf("Expected a *ConflictError with the current revision, got %v", err)
+				}
+				return
+			}
+			if rev != tc.expectedRev {
+				t.Errorf("Expected rev %s, got %s", tc.expectedRev, rev)
+			}
+			if called != (tc.expectedBulk != nil) {
+				t.Errorf("Expected resolver called: %v, got %v", tc.expectedBulk != nil, called)
+			}
+			if !reflect.DeepEqual(bulk.Docs, tc.expectedBulk) {
+				t.Errorf("Expected bulk docs %v, got %v", tc.expectedBulk, bulk.Docs)
+			}
+		})
+	}
+}
+
+func TestResolveConflictsResolverError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("open_revs") != "" {
+			w.Write([]byte(`[{"ok":{"_id":"doc1","_rev":"2-c"}},{"ok":{"_id":"doc1","_rev":"2-b"}}]`))
+			return
+		}
+		if r.Method != http.MethodGet {
+			t.Errorf("Unexpected %s request", r.Method)
+		}
+		w.Write([]byte(`{"_id":"doc1","_rev":"2-c","_conflicts":["2-b"]}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	errUndecided := errors.New("undecided")
+	_, err := db.ResolveConflicts(context.Background(), "doc1", func(revs []json.RawMessage) (any, error) {
+		return nil, errUndecided
+	})
+	if !errors.Is(err, errUndecided) {
+		t.Errorf("Expected the resolver error, got %v", err)
+	}
+}
@@ -2,38 +2,94 @@ package couchdb
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"time"
+	"net/url"
 )
 
 type CouchServiceI interface {
 	GetDB(ctx context.Context, name string, createIfItDoesntExist bool) (*Database, error)
+	DBsInfo(ctx context.Context, names []string) ([]DBsInfoResult, error)
+	AllDBs(ctx context.Context, opts AllDBsOptions) ([]string, error)
+	DeleteDB(ctx context.Context, name string) error
+	Users() *Users
+	Replicate(ctx context.Context, replication ReplicationRequest, opts ...RequestOption) (*ReplicationResponse, error)
+	Replicator() *Replicator
+	SchedulerJobs(ctx context.Context) ([]SchedulerJob, error)
+	SchedulerDocs(ctx context.Context, replicatorDB string) ([]SchedulerDoc, error)
+	ActiveTasks(ctx context.Context) ([]ActiveTask, error)
+	ServerInfo(ctx context.Context) (*ServerInfo, error)
+	Up(ctx context.Context) error
+	UUIDs(ctx context.Context, count int) ([]string, error)
+	Membership(ctx context.Context) (*Membership, error)
+	Node(name string) *Node
+	ClusterSetup(ctx context.Context, action ClusterSetupAction) error
+	ClusterSetupState(ctx context.Context, ensureDBsExist []string) (string, error)
+	Resharder() *Resharder
 }
 
 type CouchService struct {
-	baseURL string
+	httpClient *CustomHTTPClient
 }
 
-func GetInstance(baseURL, username, password string) CouchServiceI {
+// New creates a CouchService for the CouchDB server at baseURL, configured with opts.
+// The options customize the HTTP client shared by every database handle obtained from the service;
+// without them, requests are not authenticated, time out after 30 seconds and are attempted up to 5 times.
+//
+// Parameters:
+//   - baseURL: The URL of the server, with an http or https scheme.
+//   - opts: The options of the client, e.g. WithAuth, WithRetries or WithTimeout.
+//
+// Returns:
+//   - *CouchService: The service.
+//   - error: An error if the URL is invalid or the server cannot be reached.
+//
+// Example:
+//
+//	cs, err := couchdb.New("https://couch.example.com",
+//	    couchdb.WithAuth("admin", "s3cret"),
+//	    couchdb.WithRetries(3),
+//	    couchdb.WithTimeout(10*time.Second),
+//	)
+//	if err != nil {
+//	    log.Fatalf("Error connecting to CouchDB: %v", err)
+//	}
+func New(baseURL string, opts ...Option) (*CouchService, error) {
 	baseURL = addSlashIfNeeded(baseURL)
 
-	if !isValidURLScheme(baseURL) {
-		panic("invalid url scheme")
-	}
-
-	authenticatedURL, err := formAuthenticatedURL(baseURL, username, password)
+	parsedURL, err := url.Parse(baseURL)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid url scheme %q: must be http or https", parsedURL.Scheme)
+	}
+	if parsedURL.Host == "" {
+		return nil, fmt.Errorf("invalid url: missing host")
 	}
 
-	if err = testURLWithHEAD(authenticatedURL); err != nil {
-		panic(err)
+	httpClient := NewCustomHTTPClient(baseURL, defaultMaxRetries, defaultRetryWait, defaultTimeout, opts...)
+	if err := httpClient.applyCredentials(); err != nil {
+		return nil, err
 	}
 
-	cs := &CouchService{
-		baseURL: authenticatedURL,
+	if err := httpClient.ping(context.Background()); err != nil {
+		return nil, fmt.Errorf("error connecting to server: %w", err)
 	}
 
+	return &CouchService{httpClient: httpClient}, nil
+}
+
+// GetInstance creates a CouchService for the CouchDB server at baseURL, authenticating with the given credentials.
+// It is equivalent to New with a WithAuth option preceding opts, but panics if the URL is invalid or the server
+// cannot be reached.
+//
+// Deprecated: Use New, which returns an error instead of panicking.
+func GetInstance(baseURL, username, password string, opts ...Option) CouchServiceI {
+	cs, err := New(baseURL, append([]Option{WithAuth(username, password)}, opts...)...)
+	if err != nil {
+		panic(err)
+	}
 	return cs
 }
 
@@ -56,7 +112,7 @@ func GetInstance(baseURL, username, password string) CouchServiceI {
 //   - An error, if any, encountered during the retrieval or creation of the database.
 //     If the operation is successful, it returns nil.
 func (c *CouchService) GetDB(ctx context.Context, name string, createIfItDoesntExist bool) (*Database, error) {
-	httpClient := NewCustomHTTPClient(c.baseURL, 5, 2*time.Second, 30*time.Second)
+	httpClient := c.httpClient
 	respCode, respBody, err := httpClient.Head(ctx, name)
 	if err != nil {
 		return nil, fmt.Errorf("error getting database: %w", err)
@@ -72,7 +128,7 @@ func (c *CouchService) GetDB(ctx context.Context, name string, createIfItDoesntE
 			}
 			return nil, ErrNotFound
 		}
-		return nil, fmt.Errorf("error getting database: %d - %s", respCode, string(respBody))
+		return nil, responseError("error getting database", respCode, respBody)
 	}
 	return &Database{
 		httpClient: httpClient,
@@ -80,6 +136,60 @@ func (c *CouchService) GetDB(ctx context.Context, name string, createIfItDoesntE
 	}, nil
 }
 
+// AllDBsOptions are the query options of the _all_dbs endpoint.
+type AllDBsOptions struct {
+	StartKey   string // Return database names starting with this one
+	EndKey     string // Stop returning database names after this one
+	Limit      int    // Maximum number of names to return; 0 means no limit
+	Skip       int    // Number of names to skip
+	Descending bool   // Return names in descending order; StartKey and EndKey must be swapped accordingly
+}
+
+// query encodes the options as query parameters, which are the same as those of _all_docs.
+func (o AllDBsOptions) query() (url.Values, error) {
+	return AllDocsOptions{StartKey: o.StartKey, EndKey: o.EndKey, Limit: o.Limit, Skip: o.Skip, Descending: o.Descending}.query()
+}
+
+// AllDBs lists the names of the databases on the server, e.g. to discover tenant databases.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - opts: The query options.
+//
+// Returns:
+//   - []string: The database names, sorted.
+//   - error: An error, if any, encountered while listing the databases.
+//
+// Example:
+//
+//	tenants, err := cs.AllDBs(ctx, couchdb.AllDBsOptions{StartKey: "tenant_", EndKey: "tenant_\uffff"})
+//	if err != nil {
+//	    log.Fatalf("Error listing databases: %v", err)
+//	}
+func (c *CouchService) AllDBs(ctx context.Context, opts AllDBsOptions) ([]string, error) {
+	values, err := opts.query()
+	if err != nil {
+		return nil, fmt.Errorf("error encoding all dbs options: %w", err)
+	}
+
+	respCode, respBody, err := c.httpClient.Get(ctx, withQuery("_all_dbs", values))
+	if err != nil {
+		return nil, fmt.Errorf("error listing databases: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error listing databases", respCode, respBody)
+	}
+
+	var names []string
+	err = json.Unmarshal(respBody, &names)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling database names: %w", err)
+	}
+
+	return names, nil
+}
+
 // createDB creates a new database with the specified name.
 //
 // This function sends an HTTP PUT request to create a new database with the given name.
@@ -102,7 +212,37 @@ func createDB(ctx context.Context, c *CustomHTTPClient, dbName string) error {
 		return fmt.Errorf("error creating db: %w", err)
 	}
 	if respCode != 201 && respCode != 202 {
-		return fmt.Errorf("error creating db: %d - %s", respCode, string(respBody))
+		return responseError("error creating db", respCode, respBody)
 	}
 	return nil
 }
+
+// DeleteDB deletes the database with the specified name, along with all its documents and indexes.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - name: The name of the database to delete.
+//
+// Returns:
+//   - An error, if any, encountered during the deletion of the database.
+//     ErrNotFound is returned if the database doesn't exist.
+//
+// Example:
+//
+//	db, err := cs.GetDB(ctx, "test_orders", true)
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	t.Cleanup(func() { _ = cs.DeleteDB(context.Background(), "test_orders") })
+func (c *CouchService) DeleteDB(ctx context.Context, name string) error {
+	respCode, respBody, err := c.httpClient.Delete(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error deleting db: %w", err)
+	}
+
+	if respCode != 200 && respCode != 202 {
+		return responseError("error deleting db", respCode, respBody)
+	}
+
+	return nil
+}
@@ -1,7 +1,13 @@
 package couchdb
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestGetInstance(t *testing.T) {
@@ -47,3 +53,200 @@ func TestGetInstance(t *testing.T) {
 		})
 	}
 }
+
+func TestNew(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	testCases := []struct {
+		name          string
+		baseURL       string
+		expectedError bool
+	}{
+		{name: "valid url", baseURL: server.URL},
+		{name: "missing scheme", baseURL: "example.com", expectedError: true},
+		{name: "unsupported scheme", baseURL: "ftp://example.com", expectedError: true},
+		{name: "missing host", baseURL: "http://", expectedError: true},
+		{name: "unreachable server", baseURL: unreachable.URL, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs, err := New(tc.baseURL, WithRetries(1), WithTimeout(time.Second))
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("Expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cs.httpClient.maxRetries != 1 || cs.httpClient.timeout != time.Second || cs.httpClient.retryWait != defaultRetryWait {
+				t.Errorf("Expected the options to override only the given defaults, got %+v", cs.httpClient)
+			}
+		})
+	}
+}
+
+func TestNewRequireValidUser(t *testing.T) {
+	var logins int32
+	sessionServer := newSessionServer(t, &logins)
+	defer sessionServer.Close()
+
+	// basicServer rejects requests without credentials, like a server with require_valid_user set.
+	basicServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "jane" || password != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer basicServer.Close()
+
+	testCases := []struct {
+		name          string
+		baseURL       string
+		opts          []Option
+		expectedError error
+	}{
+		{name: "basic auth header", baseURL: basicServer.URL, opts: []Option{WithAuth("jane", "s3cret"), WithBasicAuth()}},
+		{name: "cookie auth", baseURL: sessionServer.URL, opts: []Option{WithAuth("jane", "s3cret"), WithCookieAuth()}},
+		{name: "missing credentials", baseURL: basicServer.URL, expectedError: ErrUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.baseURL, append([]Option{WithRetries(1)}, tc.opts...)...)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected error %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestNewWithAuth(t *testing.T) {
+	var user, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ = r.BasicAuth()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "credentials in url", opts: []Option{WithAuth("admin", "s3cret")}},
+		{name: "basic auth header", opts: []Option{WithAuth("admin", "s3cret"), WithBasicAuth()}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs, err := New(server.URL, tc.opts...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			user, password = "", ""
+			if _, _, err := cs.httpClient.Head(context.Background(), ""); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if user != "admin" || password != "s3cret" {
+				t.Errorf("Expected the credentials to be sent, got %q:%q", user, password)
+			}
+		})
+	}
+}
+
+func TestAllDBs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		opts          AllDBsOptions
+		expectedQuery map[string]string
+		statusCode    int
+		body          string
+		expectedNames []string
+		expectedError error
+	}{
+		{name: "all databases", statusCode: 200, body: `["_replicator","_users","tenant_a"]`, expectedNames: []string{"_replicator", "_users", "tenant_a"}},
+		{
+			name:          "range of databases",
+			opts:          AllDBsOptions{StartKey: "tenant_", EndKey: "tenant_\uffff", Limit: 2},
+			expectedQuery: map[string]string{"startkey": `"tenant_"`, "endkey": "\"tenant_\uffff\"", "limit": "2"},
+			statusCode:    200,
+			body:          `["tenant_a","tenant_b"]`,
+			expectedNames: []string{"tenant_a", "tenant_b"},
+		},
+		{name: "not an admin", statusCode: 403, body: `{"error":"forbidden","reason":"You are not a server admin."}`, expectedError: ErrForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/_all_dbs" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				query := r.URL.Query()
+				if len(query) != len(tc.expectedQuery) {
+					t.Errorf("Expected query %v, got %v", tc.expectedQuery, query)
+				}
+				for key, value := range tc.expectedQuery {
+					if query.Get(key) != value {
+						t.Errorf("Expected %s=%s, got %q", key, value, query.Get(key))
+					}
+				}
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+			names, err := cs.AllDBs(context.Background(), tc.opts)
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if !reflect.DeepEqual(names, tc.expectedNames) {
+				t.Errorf("Expected names %v, got %v", tc.expectedNames, names)
+			}
+		})
+	}
+}
+
+func TestDeleteDB(t *testing.T) {
+	testCases := []struct {
+		name          string
+		db            string
+		statusCode    int
+		body          string
+		expectedError error
+	}{
+		{name: "deleted", db: "test_orders", statusCode: 200, body: `{"ok":true}`},
+		{name: "deletion accepted without quorum", db: "test_orders", statusCode: 202, body: `{"ok":true}`},
+		{name: "missing database", db: "missing", statusCode: 404, body: `{"error":"not_found","reason":"Database does not exist."}`, expectedError: ErrNotFound},
+		{name: "not an admin", db: "test_orders", statusCode: 401, body: `{"error":"unauthorized","reason":"You are not a server admin."}`, expectedError: ErrUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete || r.URL.Path != "/"+tc.db {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+			if err := cs.DeleteDB(context.Background(), tc.db); !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected error %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -1,26 +1,44 @@
 package couchdb
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"reflect"
+	"strings"
+	"time"
 )
 
 type Database struct {
-	httpClient *CustomHTTPClient
-	dbName     string
+	httpClient   *CustomHTTPClient
+	dbName       string
+	loggerScoped bool // Whether the logger of httpClient already has the "db" attribute, as set by WithLogger
 }
 
 type Document struct {
-	ID  string `json:"_id,omitempty"`
-	Rev string `json:"_rev,omitempty"`
+	ID          string                `json:"_id,omitempty"`
+	Rev         string                `json:"_rev,omitempty"`
+	Attachments map[string]Attachment `json:"_attachments,omitempty"`
+	// Conflicts lists the conflicting leaf revisions of the document; only set when requested with GetDocOptions.Conflicts.
+	// CouchDB ignores it on writes, so a document read this way can be written back as is.
+	Conflicts []string `json:"_conflicts,omitempty"`
+	// Revisions is the revision history of the document; only set when requested with GetDocOptions.Revs.
+	Revisions *Revisions `json:"_revisions,omitempty"`
+	// RevsInfo is the revision history of the document with the availability of each revision;
+	// only set when requested with GetDocOptions.RevsInfo. CouchDB ignores it on writes.
+	RevsInfo []RevInfo `json:"_revs_info,omitempty"`
 }
 
 // CreateDoc creates a new document in the database.
 //
+// If doc is a map or a pointer to a struct embedding Document, the generated ID and the revision of the new
+// document are written back to it, so it can be updated right away.
+//
 // This function sends an HTTP POST request to create a new document in the database with the provided context and document data.
 // It returns an error if there was a problem sending the request or if the response status code is not 200 (OK).
 // If an error occurs during the HTTP request, it is wrapped and returned with additional context.
@@ -29,6 +47,7 @@ type Document struct {
 // Parameters:
 //   - ctx: The context.Context for the HTTP request.
 //   - doc: The document data to be created in the database. It can be of any type.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
 //
 // Returns:
 //   - An error, if any, encountered during the creation of the document.
@@ -46,14 +65,14 @@ type Document struct {
 //
 // Note: This function assumes that db.httpClient is a CustomHTTPClient instance with methods for sending HTTP requests.
 // The response body is expected to contain additional information in case of errors.
-func (db *Database) CreateDoc(ctx context.Context, doc any) (*CreateDocResponseType, error) {
-	respCode, respBody, err := db.httpClient.Post(ctx, db.dbName, doc)
+func (db *Database) CreateDoc(ctx context.Context, doc any, opts ...RequestOption) (*CreateDocResponseType, error) {
+	respCode, respBody, err := db.httpClient.Post(ctx, db.dbName, doc, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error creating doc: %w", err)
 	}
 
 	if respCode != 200 && respCode != 201 {
-		return nil, fmt.Errorf("error creating doc: %d - %s", respCode, string(respBody))
+		return nil, responseError("error creating doc", respCode, respBody)
 	}
 
 	var createDocResponse CreateDocResponseType
@@ -62,6 +81,54 @@ func (db *Database) CreateDoc(ctx context.Context, doc any) (*CreateDocResponseT
 	if err != nil {
 		return nil, fmt.Errorf("error unmarshalling create doc response: %w", err)
 	}
+	setDocMeta(doc, createDocResponse.ID, createDocResponse.Rev)
+
+	return &createDocResponse, nil
+}
+
+// CreateDocWithID creates a new document with the given ID, instead of the UUID generated by the server in CreateDoc.
+//
+// Unlike UpdateDoc, it doesn't require doc to carry a revision: it is meant for documents that don't exist yet,
+// and fails with ErrConflict if one with the same ID already does. As in CreateDoc, the ID and revision are
+// written back to doc.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - id: The ID of the new document.
+//   - doc: The document data. It can be of any type.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - *CreateDocResponseType: The ID and revision of the created document.
+//   - error: An error, if any, encountered during the creation of the document.
+//
+// Example:
+//
+//	resp, err := db.CreateDocWithID(ctx, "tenant:42:invoice:1001", invoice)
+//	if errors.Is(err, couchdb.ErrConflict) {
+//	    log.Printf("Invoice already exists")
+//	}
+func (db *Database) CreateDocWithID(ctx context.Context, id string, doc any, opts ...RequestOption) (*CreateDocResponseType, error) {
+	if id == "" {
+		return nil, ErrMissingID
+	}
+
+	respCode, respBody, err := db.httpClient.Put(ctx, docPath(db.dbName, id), doc, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating doc: %w", err)
+	}
+
+	if respCode != 201 && respCode != 202 {
+		return nil, responseError("error creating doc", respCode, respBody)
+	}
+
+	var createDocResponse CreateDocResponseType
+
+	err = json.Unmarshal(respBody, &createDocResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling create doc response: %w", err)
+	}
+	setDocMeta(doc, createDocResponse.ID, createDocResponse.Rev)
 
 	return &createDocResponse, nil
 }
@@ -84,6 +151,7 @@ type CreateDocResponseType struct {
 //   - ctx: The context.Context for the HTTP request.
 //   - id: The ID of the document to retrieve from the database.
 //   - doc: A pointer to a struct where the retrieved document data will be populated.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
 //
 // Returns:
 //   - An error, if any, encountered during the retrieval and unmarshalling of the document.
@@ -101,21 +169,136 @@ type CreateDocResponseType struct {
 //	if err != nil {
 //	    log.Fatalf("Error getting document: %v", err)
 //	}
-func (db *Database) GetDoc(ctx context.Context, id string, doc any) error {
+func (db *Database) GetDoc(ctx context.Context, id string, doc any, opts ...RequestOption) error {
+	return db.GetDocWithOptions(ctx, id, GetDocOptions{}, doc, opts...)
+}
+
+// GetDocOptions are the query options accepted when retrieving a document.
+type GetDocOptions struct {
+	// Attachments includes the content of the attachments, base64-encoded by the server and decoded into
+	// the Data field of each Attachment. Without it, attachments are returned as stubs holding only their metadata.
+	Attachments bool
+	// AttsSince limits the attachments whose content is included to those added or changed after these revisions;
+	// the others are returned as stubs. It is only used together with Attachments.
+	AttsSince []string
+	// Conflicts includes the conflicting leaf revisions of the document, which the server otherwise hides behind
+	// the winning one, in the Conflicts field of Document. Documents without conflicts are returned without it.
+	Conflicts bool
+	// Revs includes the revision history of the document, from its current revision back to the oldest one
+	// the database remembers, in the Revisions field of Document.
+	Revs bool
+	// RevsInfo includes the revision history of the document together with whether the content of each revision
+	// is still available, in the RevsInfo field of Document.
+	RevsInfo bool
+	// Rev retrieves the given revision of the document instead of the current one, e.g. to show what it looked like
+	// before the last update. Only revisions whose content hasn't been removed on compaction can be retrieved.
+	Rev string
+}
+
+// query encodes the options as query parameters of the document endpoint.
+func (o GetDocOptions) query() (url.Values, error) {
+	values := url.Values{}
+	if o.Attachments {
+		values.Set("attachments", "true")
+		if len(o.AttsSince) > 0 {
+			attsSince, err := json.Marshal(o.AttsSince)
+			if err != nil {
+				return nil, err
+			}
+			values.Set("atts_since", string(attsSince))
+		}
+	}
+	if o.Conflicts {
+		values.Set("conflicts", "true")
+	}
+	if o.Revs {
+		values.Set("revs", "true")
+	}
+	if o.RevsInfo {
+		values.Set("revs_info", "true")
+	}
+	if o.Rev != "" {
+		values.Set("rev", o.Rev)
+	}
+	return values, nil
+}
+
+// GetDocWithOptions retrieves a document like GetDoc, applying the given query options.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - id: The ID of the document to retrieve from the database.
+//   - opts: The options of the request.
+//   - doc: A pointer to a struct where the retrieved document data will be populated.
+//     Embedding Document gives access to the attachments through its Attachments field.
+//   - reqOpts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - An error, if any, encountered during the retrieval and unmarshalling of the document.
+//     If the retrieval and unmarshalling are successful, it returns nil.
+//
+// Example:
+//
+//	type Invoice struct {
+//	    couchdb.Document
+//	    Number string `json:"number"`
+//	}
+//
+//	var invoice Invoice
+//	err := db.GetDocWithOptions(ctx, "invoice:42", couchdb.GetDocOptions{Attachments: true}, &invoice)
+//	if err != nil {
+//	    log.Fatalf("Error getting document: %v", err)
+//	}
+//	pdf := invoice.Attachments["invoice.pdf"].Data
+func (db *Database) GetDocWithOptions(ctx context.Context, id string, opts GetDocOptions, doc any, reqOpts ...RequestOption) error {
 	if !isValidParam(doc) {
 		return fmt.Errorf("doc parameter must be a pointer to a struct")
 	}
 
-	respCode, respBody, err := db.httpClient.Get(ctx, fmt.Sprintf("%s/%s", db.dbName, id))
+	return db.getDoc(ctx, id, opts, doc, reqOpts...)
+}
+
+// GetDocT retrieves a document and unmarshals it into a new value of type T,
+// so the document type is checked at compile time instead of through an untyped pointer.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - db: The database to retrieve the document from.
+//   - id: The ID of the document to retrieve.
+//
+// Returns:
+//   - *T: The retrieved document.
+//   - error: ErrNotFound if the document doesn't exist, or any other error encountered.
+//
+// Example:
+//
+//	person, err := couchdb.GetDocT[Person](ctx, db, "john")
+//	if err != nil {
+//	    log.Fatalf("Error getting document: %v", err)
+//	}
+//	fmt.Println(person.Name)
+func GetDocT[T any](ctx context.Context, db *Database, id string) (*T, error) {
+	var doc T
+	if err := db.getDoc(ctx, id, GetDocOptions{}, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// getDoc retrieves a document and unmarshals it into doc, which is not validated.
+func (db *Database) getDoc(ctx context.Context, id string, opts GetDocOptions, doc any, reqOpts ...RequestOption) error {
+	values, err := opts.query()
+	if err != nil {
+		return fmt.Errorf("error encoding get doc options: %w", err)
+	}
+
+	respCode, respBody, err := db.httpClient.Get(ctx, withQuery(docPath(db.dbName, id), values), reqOpts...)
 	if err != nil {
 		return fmt.Errorf("error getting doc: %w", err)
 	}
 
 	if respCode != 200 {
-		if errFromMap, ok := codeToError[respCode]; ok {
-			return errFromMap
-		}
-		return fmt.Errorf("error getting doc: %d - %s", respCode, string(respBody))
+		return responseError("error getting doc", respCode, respBody)
 	}
 
 	err = json.Unmarshal(respBody, doc)
@@ -130,51 +313,165 @@ func (db *Database) GetDoc(ctx context.Context, id string, doc any) error {
 //
 // This function either creates a new document with the specified ID or updates an existing document with a new revision.
 // To update an existing document, the current revision must be provided in the document body, as a query parameter ("rev"),
-// or in the "If-Match" request header.
+// or in the "If-Match" request header. The new revision is written back to doc if it is a map or a pointer to a struct
+// embedding Document, so a follow-up update doesn't fail with a conflict.
 //
 // Parameters:
 //   - ctx: The context.Context for the HTTP request.
 //   - doc: The document data to be created or updated. It can be of any type, but it must contain the current revision information for updates.
 //   - id: The ID of the document to be created or updated in the database.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
 //
 // Returns:
-//   - An error, if any, encountered during the creation or update of the document.
-//     If the operation is successful, it returns nil.
+//   - *UpdateDocResponse: The ID and new revision of the document, which a follow-up update must send.
+//   - error: An error, if any, encountered during the creation or update of the document.
+//     A *ConflictError is returned if the revision sent is not the current one.
 //
 // Example:
 //
 //	// Update an existing document
-//	err := db.UpdateDoc(ctx, map[string]interface{}{
+//	resp, err := db.UpdateDoc(ctx, "existing_doc_id", map[string]interface{}{
 //	    "_id":  "existing_doc_id",
 //	    "_rev": "current_revision",
 //	    "key":  "new_value",
-//	}, "existing_doc_id")
+//	})
 //	if err != nil {
 //	    log.Fatalf("Error updating document: %v", err)
 //	}
+//	fmt.Println("New revision:", resp.Rev)
 //
 //	// Create a new document
-//	err = db.UpdateDoc(ctx, map[string]interface{}{
+//	_, err = db.UpdateDoc(ctx, "new_doc_id", map[string]interface{}{
 //	    "_id":  "new_doc_id",
 //	    "key":  "value",
-//	}, "new_doc_id")
+//	})
 //	if err != nil {
 //	    log.Fatalf("Error creating document: %v", err)
 //	}
-func (db *Database) UpdateDoc(ctx context.Context, id string, doc any) error {
+func (db *Database) UpdateDoc(ctx context.Context, id string, doc any, opts ...RequestOption) (*UpdateDocResponse, error) {
 	if err := checkParameter(doc); err != nil {
-		return fmt.Errorf("doc check failed: %w", err)
+		return nil, fmt.Errorf("doc check failed: %w", err)
 	}
 
-	respCode, respBody, err := db.httpClient.Put(ctx, fmt.Sprintf("%s/%s", db.dbName, id), doc)
+	resp, err := db.putDoc(ctx, id, doc, opts...)
 	if err != nil {
-		return fmt.Errorf("error updating doc: %w", err)
+		return nil, err
 	}
-	if respCode != 200 && respCode != 201 {
-		return fmt.Errorf("error updating doc: %d - %s", respCode, string(respBody))
+	setDocMeta(doc, "", resp.Rev)
+	return resp, nil
+}
+
+// putDoc writes a document, whose revision is not checked, and parses the response.
+func (db *Database) putDoc(ctx context.Context, id string, doc any, opts ...RequestOption) (*UpdateDocResponse, error) {
+	respCode, respBody, err := db.httpClient.Put(ctx, docPath(db.dbName, id), doc, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error updating doc: %w", err)
+	}
+	if respCode == 409 {
+		return nil, db.conflictError(ctx, id, newError("error updating doc", respCode, respBody))
+	}
+	if respCode != 200 && respCode != 201 && respCode != 202 {
+		return nil, responseError("error updating doc", respCode, respBody)
 	}
 
-	return nil
+	var updateDocResponse UpdateDocResponse
+	err = json.Unmarshal(respBody, &updateDocResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling update doc response: %w", err)
+	}
+
+	return &updateDocResponse, nil
+}
+
+// UpdateDocResponse is the response to a document write.
+type UpdateDocResponse struct {
+	ID  string `json:"id"`
+	Ok  bool   `json:"ok"`
+	Rev string `json:"rev"`
+}
+
+// defaultUpsertAttempts is the default number of times Upsert tries to write a document that keeps being updated concurrently.
+const defaultUpsertAttempts = 5
+
+// WithUpsertAttempts sets how many times Upsert tries to write a document that keeps being updated concurrently
+// before giving up with a *ConflictError. It defaults to 5; a value of 1 or less makes a single attempt.
+func WithUpsertAttempts(attempts int) Option {
+	return func(c *CustomHTTPClient) {
+		c.upsertAttempts = attempts
+	}
+}
+
+// Upsert writes doc under the given ID, whether or not the document already exists, and returns the new revision.
+//
+// The write is first attempted with the revision in doc, if any. If it is rejected with a conflict, the current
+// revision of the document is looked up and the same content is written again on top of it, up to 5 times in total
+// unless set otherwise with WithUpsertAttempts.
+// Concurrent changes made by others are therefore overwritten; use UpdateDoc to detect them instead.
+// As in UpdateDoc, the ID and new revision are written back to doc.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP requests.
+//   - id: The ID of the document.
+//   - doc: The document data. It can be of any type that marshals into a JSON object; its _id and _rev are replaced.
+//   - opts: Optional per-request options, applied to every write.
+//
+// Returns:
+//   - *UpdateDocResponse: The ID and new revision of the document.
+//   - error: An error, if any, encountered while writing the document. A *ConflictError, which matches
+//     ErrConflict, is returned if the document was still being updated concurrently once the attempts are used up.
+//
+// Example:
+//
+//	resp, err := db.Upsert(ctx, "settings:tenant-42", settings)
+//	if err != nil {
+//	    log.Fatalf("Error saving settings: %v", err)
+//	}
+func (db *Database) Upsert(ctx context.Context, id string, doc any, opts ...RequestOption) (*UpdateDocResponse, error) {
+	if id == "" {
+		return nil, ErrMissingID
+	}
+
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding doc: %w", err)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(encoded, &body); err != nil || body == nil {
+		return nil, fmt.Errorf("doc must marshal into a JSON object")
+	}
+	body["_id"] = id
+
+	for attempt := 1; ; attempt++ {
+		resp, err := db.putDoc(ctx, id, body, opts...)
+		if err == nil {
+			setDocMeta(doc, id, resp.Rev)
+			return resp, nil
+		}
+		var conflict *ConflictError
+		if !errors.As(err, &conflict) || attempt >= db.httpClient.upsertAttempts {
+			return nil, err
+		}
+
+		// The document is gone if it has no current revision, e.g. because it was deleted meanwhile.
+		if conflict.CurrentRev == "" {
+			delete(body, "_rev")
+		} else {
+			body["_rev"] = conflict.CurrentRev
+		}
+	}
+}
+
+// conflictError turns the *Error of a write to the document rejected with a conflict into a *ConflictError,
+// looking up the current revision of the document with a HEAD request. A failed lookup leaves CurrentRev empty.
+func (db *Database) conflictError(ctx context.Context, id string, couchErr *Error) error {
+	conflict := &ConflictError{Err: couchErr}
+
+	var info ResponseInfo
+	code, _, headErr := db.httpClient.Head(ctx, docPath(db.dbName, id), CaptureResponse(&info))
+	if headErr == nil && code == 200 {
+		conflict.CurrentRev = info.ETag
+	}
+	return conflict
 }
 
 // DeleteDoc deletes a document from the database using its ID.
@@ -187,6 +484,7 @@ func (db *Database) UpdateDoc(ctx context.Context, id string, doc any) error {
 // Parameters:
 //   - ctx: The context.Context for the HTTP request.
 //   - id: The ID of the document to be deleted from the database.
+//   - opts: The options of the DELETE request.
 //
 // Returns:
 //   - An error, if any, encountered during the deletion of the document.
@@ -198,7 +496,7 @@ func (db *Database) UpdateDoc(ctx context.Context, id string, doc any) error {
 //	if err != nil {
 //	    log.Fatalf("Error deleting document: %v", err)
 //	}
-func (db *Database) DeleteDoc(ctx context.Context, id string) error {
+func (db *Database) DeleteDoc(ctx context.Context, id string, opts ...RequestOption) error {
 	var doc map[string]interface{}
 	err := db.GetDoc(ctx, id, &doc)
 	if err != nil {
@@ -207,45 +505,230 @@ func (db *Database) DeleteDoc(ctx context.Context, id string) error {
 
 	rev, _ := doc["_rev"].(string)
 
-	respCode, respBody, err := db.httpClient.Delete(ctx, fmt.Sprintf("%s/%s?rev=%s", db.dbName, id, rev))
+	return db.DeleteDocRev(ctx, id, rev, opts...)
+}
+
+// DeleteDocRev deletes the given revision of a document, skipping the lookup of the current revision done by DeleteDoc.
+// When the caller already holds the revision, this halves the latency of the deletion, and it fails with
+// a *ConflictError instead of deleting a revision the caller hasn't seen if the document was updated meanwhile.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - id: The ID of the document to be deleted from the database.
+//   - rev: The revision to delete, which must be the current one.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - An error, if any, encountered during the deletion of the document.
+//     If the deletion is successful, it returns nil.
+//
+// Example:
+//
+//	err := db.DeleteDocRev(ctx, invoice.ID, invoice.Rev)
+//	if errors.Is(err, couchdb.ErrConflict) {
+//	    log.Printf("Invoice changed since it was read")
+//	}
+func (db *Database) DeleteDocRev(ctx context.Context, id, rev string, opts ...RequestOption) error {
+	if rev == "" {
+		return ErrMissingRev
+	}
+
+	values := url.Values{}
+	values.Set("rev", rev)
+
+	respCode, respBody, err := db.httpClient.Delete(ctx, withQuery(docPath(db.dbName, id), values), opts...)
 	if err != nil {
 		return fmt.Errorf("error deleting doc: %w", err)
 	}
 
+	if respCode == 409 {
+		return db.conflictError(ctx, id, newError("error deleting doc", respCode, respBody))
+	}
 	if respCode != 200 && respCode != 202 {
-		return fmt.Errorf("error deleting doc: %d - %s", respCode, string(respBody))
+		return responseError("error deleting doc", respCode, respBody)
 	}
 
 	return nil
 }
 
-func (db *Database) CreateDesignDoc(ctx context.Context, designDoc string, views map[string]ViewDefinition) error {
-	docID := fmt.Sprintf("_design/%s", designDoc)
-	body := designDocument{
-		ID:         docID,
+// CopyDoc duplicates a document on the server with the COPY method, without transferring its content.
+// Attachments are copied along with the document.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - sourceID: The ID of the document to copy.
+//   - targetID: The ID of the copy.
+//   - targetRev: The current revision of the target document, to overwrite it; empty if it doesn't exist.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - *UpdateDocResponse: The ID and revision of the copy.
+//   - error: An error, if any, encountered while copying the document. ErrNotFound is returned if the source
+//     doesn't exist, and ErrConflict if the target exists and targetRev is not its current revision.
+//
+// Example:
+//
+//	resp, err := db.CopyDoc(ctx, "template:invoice", "invoice:1001", "")
+//	if err != nil {
+//	    log.Fatalf("Error copying document: %v", err)
+//	}
+func (db *Database) CopyDoc(ctx context.Context, sourceID, targetID, targetRev string, opts ...RequestOption) (*UpdateDocResponse, error) {
+	destination := escapeDocID(targetID)
+	if targetRev != "" {
+		values := url.Values{}
+		values.Set("rev", targetRev)
+		destination = withQuery(escapeDocID(targetID), values)
+	}
+	opts = append([]RequestOption{WithHeader("Destination", destination)}, opts...)
+
+	respCode, respBody, err := db.httpClient.makeRequest(ctx, "COPY", docPath(db.dbName, sourceID), nil, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error copying doc: %w", err)
+	}
+
+	if respCode != 201 && respCode != 202 {
+		return nil, responseError("error copying doc", respCode, respBody)
+	}
+
+	var copyResponse UpdateDocResponse
+	err = json.Unmarshal(respBody, &copyResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling copy doc response: %w", err)
+	}
+
+	return &copyResponse, nil
+}
+
+func (db *Database) CreateDesignDoc(ctx context.Context, designDoc string, views map[string]ViewDefinition, opts ...RequestOption) error {
+	_, err := db.SyncDesignDoc(ctx, designDoc, DesignDocument{
 		Language:   "javascript",
 		Autoupdate: true,
 		Views:      views,
-	}
+	}, opts...)
+	return err
+}
+
+// SyncDesignDoc makes the design document stored on the server match designDoc, only writing it when they differ.
+//
+// Every write of a design document invalidates the indexes of its views, so calling this on each application
+// startup instead of unconditionally overwriting the design document avoids rebuilding unchanged views.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - name: The name of the design document, with or without the "_design/" prefix.
+//   - designDoc: The desired design document. Its ID and Rev are ignored.
+//   - opts: Optional per-request options, applied to both the read and the write of the design document.
+//
+// Returns:
+//   - bool: Whether the design document was created or updated.
+//   - error: An error, if any, encountered while reading or writing the design document.
+//
+// Example:
+//
+//	updated, err := db.SyncDesignDoc(ctx, "people", couchdb.DesignDocument{
+//	    Language: "javascript",
+//	    Views: map[string]couchdb.ViewDefinition{
+//	        "by_age": {Map: "function (doc) { emit(doc.age, null); }"},
+//	    },
+//	})
+//	if err != nil {
+//	    log.Fatalf("Error syncing design document: %v", err)
+//	}
+func (db *Database) SyncDesignDoc(ctx context.Context, name string, designDoc DesignDocument, opts ...RequestOption) (bool, error) {
+	name = strings.TrimPrefix(name, "_design/")
+	designDoc.ID = fmt.Sprintf("_design/%s", name)
+	designDoc.Rev = ""
 
-	var prevDoc designDocument
-	err := db.GetDoc(ctx, docID, &prevDoc)
-	if !errors.Is(err, ErrNotFound) {
-		body.Rev = prevDoc.Rev
+	current, err := db.GetDesignDoc(ctx, name, opts...)
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return false, fmt.Errorf("error getting current design doc: %w", err)
+	default:
+		designDoc.Rev = current.Rev
+		if sameDesignDoc(*current, designDoc) {
+			return false, nil
+		}
 	}
 
-	code, responseBytes, err := db.httpClient.Put(ctx, fmt.Sprintf("%s/_design/%s", db.dbName, designDoc), body)
+	code, responseBytes, err := db.httpClient.Put(ctx, docPath(db.dbName, "_design/"+name), designDoc, opts...)
 	if err != nil {
-		return fmt.Errorf("error creating design doc: %w", err)
+		return false, fmt.Errorf("error creating design doc: %w", err)
 	}
 
 	if code != 200 && code != 201 {
-		return fmt.Errorf("error creating design doc: %d - %s", code, string(responseBytes))
+		return false, responseError("error creating design doc", code, responseBytes)
+	}
+	return true, nil
+}
+
+// sameDesignDoc reports whether two design documents have the same content, regardless of their revisions.
+// They are compared through their JSON encoding, so e.g. an empty map and a nil map are considered equal.
+func sameDesignDoc(a, b DesignDocument) bool {
+	a.Rev, b.Rev = "", ""
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// GetDesignDoc retrieves a design document, e.g. so deployment tooling can inspect the views currently on the server.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - name: The name of the design document, with or without the "_design/" prefix.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - *DesignDocument: The design document.
+//   - error: ErrNotFound if the design document doesn't exist, or any other error encountered.
+func (db *Database) GetDesignDoc(ctx context.Context, name string, opts ...RequestOption) (*DesignDocument, error) {
+	var designDoc DesignDocument
+	err := db.GetDoc(ctx, fmt.Sprintf("_design/%s", strings.TrimPrefix(name, "_design/")), &designDoc, opts...)
+	if err != nil {
+		return nil, err
 	}
+	return &designDoc, nil
+}
+
+// DeleteDesignDoc deletes a design document, e.g. when retiring obsolete views.
+// The current revision is fetched first, so the deletion applies to whatever version is on the server.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - name: The name of the design document, with or without the "_design/" prefix.
+//   - opts: Optional per-request options, applied to both the read of the current revision and the deletion.
+//
+// Returns:
+//   - An error, if any, encountered while deleting the design document.
+//     ErrNotFound is returned if the design document doesn't exist.
+func (db *Database) DeleteDesignDoc(ctx context.Context, name string, opts ...RequestOption) error {
+	name = strings.TrimPrefix(name, "_design/")
+
+	designDoc, err := db.GetDesignDoc(ctx, name, opts...)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("error getting design doc to delete: %w", err)
+	}
+
+	values := url.Values{}
+	values.Set("rev", designDoc.Rev)
+
+	respCode, respBody, err := db.httpClient.Delete(ctx, withQuery(docPath(db.dbName, "_design/"+name), values), opts...)
+	if err != nil {
+		return fmt.Errorf("error deleting design doc: %w", err)
+	}
+
+	if respCode != 200 && respCode != 202 {
+		return responseError("error deleting design doc", respCode, respBody)
+	}
+
 	return nil
 }
 
-type designDocument struct {
+// DesignDocument is a design document, holding the views and other functions of a database.
+type DesignDocument struct {
 	ID                string                    `json:"_id"`
 	Rev               string                    `json:"_rev,omitempty"`
 	Language          string                    `json:"language"`
@@ -257,6 +740,8 @@ type designDocument struct {
 	Updates           map[string]string         `json:"updates,omitempty"`
 	ValidateDocUpdate string                    `json:"validate_doc_update,omitempty"`
 	Views             map[string]ViewDefinition `json:"views,omitempty"`
+	SearchIndexes     map[string]SearchIndex    `json:"indexes,omitempty"` // Search indexes queried with Search, by name
+	NouveauIndexes    map[string]NouveauIndex   `json:"nouveau,omitempty"` // Nouveau indexes queried with NouveauSearch, by name
 	Autoupdate        bool                      `json:"autoupdate,omitempty"`
 }
 
@@ -274,32 +759,65 @@ type ViewResponse struct {
 	UpdateSeq any   `json:"update_seq"` // Current update sequence for the database
 }
 
+// ViewParams are the parameters of a view query, as described [here](https://docs.couchdb.org/en/stable/api/ddoc/views.html#db-design-design-doc-view-view-name).
+// They are sent as the JSON body of the query, so keys can be of any type that marshals to the JSON value emitted by the view.
+type ViewParams struct {
+	Key          any    `json:"key,omitempty"`           // Return only rows matching this key
+	Keys         []any  `json:"keys,omitempty"`          // Return only rows matching any of these keys, in this order
+	StartKey     any    `json:"start_key,omitempty"`     // Return rows starting with this key
+	EndKey       any    `json:"end_key,omitempty"`       // Stop returning rows after this key
+	InclusiveEnd *bool  `json:"inclusive_end,omitempty"` // Whether EndKey is included; the server default is true
+	Limit        int    `json:"limit,omitempty"`         // Maximum number of rows to return; 0 means no limit
+	Skip         int    `json:"skip,omitempty"`          // Number of rows to skip
+	Descending   bool   `json:"descending,omitempty"`    // Return rows in descending key order; StartKey and EndKey must be swapped accordingly
+	Reduce       *bool  `json:"reduce,omitempty"`        // Whether to run the reduce function; the server default is true if the view has one
+	Group        bool   `json:"group,omitempty"`         // Group the reduce results by key
+	GroupLevel   int    `json:"group_level,omitempty"`   // Group the reduce results by the first GroupLevel elements of array keys
+	IncludeDocs  bool   `json:"include_docs,omitempty"`  // Include the emitting document in each row
+	Update       string `json:"update,omitempty"`        // "true" (default), "false" or "lazy"
+	Stable       bool   `json:"stable,omitempty"`        // Whether to use the same set of shard replicas for every request
+}
+
 // View performs a query on a database view with the specified design, view, and parameters.
 //
 // Parameters:
 //   - ctx: The context for the HTTP request.
 //   - design: The design document name.
 //   - view: The name of the view within the design document.
-//   - params: The parameters for the view query as described [here](https://docs.couchdb.org/en/stable/api/ddoc/views.html#db-design-design-doc-view-view-name).
+//   - params: The parameters for the view query.
 //   - resultVar: A pointer to a struct where the view results will be unmarshalled.
 //     The struct must have a "rows" field holding a slice of structs with "id" and "key" JSON fields.
 //     If params.IncludeDocs is true, the struct must also have a "doc" JSON field.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
 //
 // Returns:
 //   - error: An error if the view query fails or if the viewResults struct does not meet the requirements.
-func (db *Database) View(ctx context.Context, design, view string, params map[string]any, resultVar interface{}) error {
+//
+// Example:
+//
+//	var result couchdb.ViewResponse
+//	err := db.View(ctx, "people", "by_age", couchdb.ViewParams{StartKey: 18, EndKey: 65, Limit: 100}, &result)
+//	if err != nil {
+//	    log.Fatalf("Error querying view: %v", err)
+//	}
+func (db *Database) View(ctx context.Context, design, view string, params ViewParams, resultVar interface{}, opts ...RequestOption) error {
+	return db.queryView(ctx, designPath(db.dbName, design, "_view", view), params, resultVar, opts...)
+}
+
+// queryView queries the view at endpoint and unmarshals the result into resultVar.
+func (db *Database) queryView(ctx context.Context, endpoint string, params ViewParams, resultVar interface{}, opts ...RequestOption) error {
 	err := checkStructForJSONFields(resultVar)
 	if err != nil {
 		return fmt.Errorf("error checking struct for JSON fields: %w", err)
 	}
 
-	code, responseBytes, err := db.httpClient.Post(ctx, fmt.Sprintf("%s/_design/%s/_view/%s", db.dbName, design, view), params)
+	code, responseBytes, err := db.httpClient.Post(ctx, endpoint, params, opts...)
 	if err != nil {
-		return fmt.Errorf("error creating design doc: %w", err)
+		return fmt.Errorf("error getting view: %w", err)
 	}
 
 	if code != 200 {
-		return fmt.Errorf("error getting view: %d - %s", code, string(responseBytes))
+		return responseError("error getting view", code, responseBytes)
 	}
 
 	// Unmarshal directly into the provided variable
@@ -311,6 +829,169 @@ func (db *Database) View(ctx context.Context, design, view string, params map[st
 	return nil
 }
 
+// ViewWithStaleFallback performs a view query like View, degrading to a stale read when the index is overloaded.
+//
+// The fresh query is given freshTimeout to complete (a non-positive value leaves it bound only by ctx and the
+// client timeout). If it times out while ctx itself is still alive, the query is retried with stable=true and
+// update=false, so CouchDB answers from the index as it currently stands instead of waiting for the indexer.
+// This keeps read paths alive while view indexing is lagging behind.
+//
+// Parameters:
+//   - ctx: The context for the HTTP requests.
+//   - design: The design document name.
+//   - view: The name of the view within the design document.
+//   - params: The parameters for the view query, as accepted by View.
+//   - freshTimeout: How long to wait for the up-to-date result before falling back.
+//   - resultVar: A pointer to a struct where the view results will be unmarshalled, as accepted by View.
+//   - opts: Optional per-request options, applied to both the fresh and the stale query.
+//
+// Returns:
+//   - bool: Whether the result was served by the stale fallback query and may be missing recent updates.
+//   - error: An error if both the fresh and the stale query fail, or if the fresh query fails for a reason other than a timeout.
+func (db *Database) ViewWithStaleFallback(ctx context.Context, design, view string, params ViewParams, freshTimeout time.Duration, resultVar interface{}, opts ...RequestOption) (bool, error) {
+	freshCtx := ctx
+	if freshTimeout > 0 {
+		var cancel context.CancelFunc
+		freshCtx, cancel = context.WithTimeout(ctx, freshTimeout)
+		defer cancel()
+	}
+
+	err := db.View(freshCtx, design, view, params, resultVar, opts...)
+	if err == nil {
+		return false, nil
+	}
+	if ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
+		return false, err
+	}
+
+	params.Stable = true
+	params.Update = "false"
+
+	if err := db.View(ctx, design, view, params, resultVar, opts...); err != nil {
+		return false, fmt.Errorf("error getting stale view after timeout: %w", err)
+	}
+	return true, nil
+}
+
+// ViewQueries runs several queries against the same view in a single request to its /queries endpoint,
+// e.g. to fetch several key ranges without one round trip per range.
+//
+// Parameters:
+//   - ctx: The context for the HTTP request.
+//   - design: The design document name.
+//   - view: The name of the view within the design document.
+//   - queries: The parameters of each query.
+//   - resultsVar: A pointer to a slice where the result of each query will be unmarshalled, in the same order as queries.
+//     The slice elements must meet the same requirements as the resultVar of View.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - error: An error if the request fails or if resultsVar does not meet the requirements.
+//
+// Example:
+//
+//	var results []couchdb.ViewResponse
+//	err := db.ViewQueries(ctx, "people", "by_age", []couchdb.ViewParams{
+//	    {StartKey: 0, EndKey: 17},
+//	    {StartKey: 65, EndKey: 120},
+//	}, &results)
+func (db *Database) ViewQueries(ctx context.Context, design, view string, queries []ViewParams, resultsVar any, opts ...RequestOption) error {
+	resultsType := reflect.TypeOf(resultsVar)
+	if resultsType == nil || resultsType.Kind() != reflect.Ptr || resultsType.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("resultsVar parameter must be a pointer to a slice")
+	}
+	err := checkStructForJSONFields(reflect.New(resultsType.Elem().Elem()).Interface())
+	if err != nil {
+		return fmt.Errorf("error checking struct for JSON fields: %w", err)
+	}
+
+	body := map[string]any{"queries": queries}
+
+	code, responseBytes, err := db.httpClient.Post(ctx, designPath(db.dbName, design, "_view", view, "queries"), body, opts...)
+	if err != nil {
+		return fmt.Errorf("error getting view queries: %w", err)
+	}
+
+	if code != 200 {
+		return responseError("error getting view queries", code, responseBytes)
+	}
+
+	result := struct {
+		Results any `json:"results"`
+	}{Results: resultsVar}
+	err = json.Unmarshal(responseBytes, &result)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling into resultsVar: %w", err)
+	}
+
+	return nil
+}
+
+// WarmViews builds the view indexes of a design document right after a deployment, instead of making the first
+// real query pay the index-build latency. All the views of a design document share a single index, so querying
+// one of them with limit=0 is enough to build the index of every view.
+//
+// The query returns once the index is up to date. Since building an index can take much longer than the client
+// timeout, the query is bound only by ctx.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP requests.
+//   - design: The name of the design document, with or without the "_design/" prefix.
+//   - opts: Optional per-request options, applied to both the read of the design document and the view query.
+//
+// Returns:
+//   - error: An error, if any, encountered while reading the design document or querying its view.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+//	defer cancel()
+//	if err := db.WarmViews(ctx, "people"); err != nil {
+//	    log.Printf("Error warming views: %v", err)
+//	}
+func (db *Database) WarmViews(ctx context.Context, design string, opts ...RequestOption) error {
+	designDoc, err := db.GetDesignDoc(ctx, design, opts...)
+	if err != nil {
+		return fmt.Errorf("error getting design doc to warm: %w", err)
+	}
+	design = strings.TrimPrefix(designDoc.ID, "_design/")
+
+	// The view queried makes no difference; the first one by name keeps the requests predictable.
+	view := ""
+	for name := range designDoc.Views {
+		if view == "" || name < view {
+			view = name
+		}
+	}
+	if view == "" {
+		return nil
+	}
+
+	r := &request{
+		method:   "POST",
+		endpoint: designPath(db.dbName, design, "_view", view),
+		body:     map[string]any{"limit": 0},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	resp, err := db.httpClient.stream(ctx, r)
+	if err != nil {
+		return fmt.Errorf("error warming view %s: %w", view, err)
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("error warming view %s: %w", view, err)
+	}
+	if resp.StatusCode != 200 {
+		return responseError(fmt.Sprintf("error warming view %s", view), resp.StatusCode, respBody)
+	}
+
+	return nil
+}
+
 // checkStructForJSONFields checks if the provided struct has the required JSON fields in each element of the 'Rows' slice.
 // It returns an error if the struct or its elements do not meet the criteria.
 func checkStructForJSONFields(resultVar interface{}) error {
@@ -343,8 +1024,8 @@ func checkStructForJSONFields(resultVar interface{}) error {
 	return nil
 }
 
-func (db *Database) DocExists(ctx context.Context, docID string) (bool, error) {
-	code, responseBody, err := db.httpClient.Head(ctx, fmt.Sprintf("%s/%s", db.dbName, docID))
+func (db *Database) DocExists(ctx context.Context, docID string, opts ...RequestOption) (bool, error) {
+	code, responseBody, err := db.httpClient.Head(ctx, docPath(db.dbName, docID), opts...)
 	if err != nil {
 		return false, fmt.Errorf("error sending HEAD request: %w", err)
 	}
@@ -357,6 +1038,6 @@ func (db *Database) DocExists(ctx context.Context, docID string) (bool, error) {
 	case http.StatusNotFound:
 		return false, nil // Document doesn't exist
 	default:
-		return false, fmt.Errorf("unexpected response status code: %d. %s", code, string(responseBody))
+		return false, responseError("error checking doc", code, responseBody)
 	}
 }
@@ -0,0 +1,473 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetDocOptionsQuery(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     GetDocOptions
+		expected string
+	}{
+		{name: "no options", opts: GetDocOptions{}, expected: ""},
+		{name: "attachments", opts: GetDocOptions{Attachments: true}, expected: "attachments=true"},
+		{
+			name:     "attachments since revisions",
+			opts:     GetDocOptions{Attachments: true, AttsSince: []string{"1-a", "2-b"}},
+			expected: "attachments=true&atts_since=%5B%221-a%22%2C%222-b%22%5D",
+		},
+		{name: "atts_since without attachments", opts: GetDocOptions{AttsSince: []string{"1-a"}}, expected: ""},
+		{name: "conflicts", opts: GetDocOptions{Conflicts: true}, expected: "conflicts=true"},
+		{name: "revision history", opts: GetDocOptions{Revs: true, RevsInfo: true}, expected: "revs=true&revs_info=true"},
+		{name: "revision", opts: GetDocOptions{Rev: "2-b"}, expected: "rev=2-b"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := tc.opts.query()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := values.Encode(); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDocumentInlineAttachments(t *testing.T) {
+	body := `{"_id":"doc","_rev":"1-a","_attachments":{
+		"hello.txt":{"content_type":"text/plain","revpos":1,"digest":"md5-abc","data":"aGVsbG8="},
+		"big.bin":{"content_type":"application/octet-stream","revpos":1,"length":1024,"stub":true}
+	}}`
+
+	var doc Base
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := string(doc.Attachments["hello.txt"].Data); got != "hello" {
+		t.Errorf("Expected decoded attachment data %q, got %q", "hello", got)
+	}
+	if stub := doc.Attachments["big.bin"]; !stub.Stub || stub.Length != 1024 || stub.Data != nil {
+		t.Errorf("Unexpected stub attachment: %+v", stub)
+	}
+}
+
+func TestGetDocConflicts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("conflicts") == "true" {
+			w.Write([]byte(`{"_id":"doc1","_rev":"2-c","_conflicts":["2-b","2-a"]}`))
+			return
+		}
+		w.Write([]byte(`{"_id":"doc1","_rev":"2-c"}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name     string
+		opts     GetDocOptions
+		expected []string
+	}{
+		{name: "without conflicts", opts: GetDocOptions{}},
+		{name: "with conflicts", opts: GetDocOptions{Conflicts: true}, expected: []string{"2-b", "2-a"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var doc Base
+			if err := db.GetDocWithOptions(context.Background(), "doc1", tc.opts, &doc); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(doc.Conflicts, tc.expected) {
+				t.Errorf("Expected conflicts %v, got %v", tc.expected, doc.Conflicts)
+			}
+		})
+	}
+}
+
+func TestGetDocEscapesID(t *testing.T) {
+	var receivedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.EscapedPath()
+		w.Write([]byte(`{"_id":"doc","_rev":"1-a"}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		id           string
+		expectedPath string
+	}{
+		{id: "jan+doe@example.com", expectedPath: "/test/jan%2Bdoe@example.com"},
+		{id: "https://example.com/page?id=1#top", expectedPath: "/test/https:%2F%2Fexample.com%2Fpage%3Fid=1%23top"},
+		{id: "year 2024", expectedPath: "/test/year%202024"},
+		{id: "_design/orders", expectedPath: "/test/_design/orders"},
+		{id: "_local/checkpoint/1", expectedPath: "/test/_local/checkpoint%2F1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.id, func(t *testing.T) {
+			var doc Base
+			if err := db.GetDoc(context.Background(), tc.id, &doc); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if receivedPath != tc.expectedPath {
+				t.Errorf("Expected path %s, got %s", tc.expectedPath, receivedPath)
+			}
+		})
+	}
+}
+
+func TestGetDocT(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/test/john":
+			w.Write([]byte(`{"_id":"john","_rev":"1-a","name":"John"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+		}
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	type person struct {
+		Document
+		Name string `json:"name"`
+	}
+
+	t.Run("existing document", func(t *testing.T) {
+		doc, err := GetDocT[person](context.Background(), db, "john")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if doc.ID != "john" || doc.Rev != "1-a" || doc.Name != "John" {
+			t.Errorf("Unexpected document: %+v", doc)
+		}
+	})
+
+	t.Run("missing document", func(t *testing.T) {
+		doc, err := GetDocT[person](context.Background(), db, "jane")
+		if !errors.Is(err, ErrNotFound) || doc != nil {
+			t.Errorf("Expected ErrNotFound and no document, got %v, %v", doc, err)
+		}
+	})
+}
+
+func TestConflictError(t *testing.T) {
+	fake := &fakeDocServer{docs: map[string]map[string]any{
+		"doc1": {"_id": "doc1", "_rev": "2-current", "name": "John"},
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name  string
+		write func() error
+	}{
+		{
+			name: "update with stale rev",
+			write: func() error {
+				_, err := db.UpdateDoc(context.Background(), "doc1", map[string]interface{}{"_id": "doc1", "_rev": "1-stale"})
+				return err
+			},
+		},
+		{
+			name: "delete after concurrent update",
+			write: func() error {
+				// The stale rev is sent once DeleteDoc has read the document, as if another client updated it meanwhile.
+				return db.DeleteDoc(context.Background(), "doc1", func(r *request) {
+					r.endpoint = "test/doc1?rev=1-stale"
+				})
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.write()
+			if !errors.Is(err, ErrConflict) {
+				t.Fatalf("Expected ErrConflict, got %v", err)
+			}
+			var conflict *ConflictError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("Expected a *ConflictError, got %T", err)
+			}
+			if conflict.CurrentRev != "2-current" {
+				t.Errorf("Expected current rev 2-current, got %q", conflict.CurrentRev)
+			}
+		})
+	}
+}
+
+func TestUpdateDocResponse(t *testing.T) {
+	fake := &fakeDocServer{docs: map[string]map[string]any{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	resp, err := db.UpdateDoc(context.Background(), "doc1", map[string]interface{}{"_id": "doc1", "_rev": ""})
+	if err != nil {
+		t.Fatalf("Unexpected error creating document: %v", err)
+	}
+	if resp.ID != "doc1" || resp.Rev != "1-x" || !resp.Ok {
+		t.Fatalf("Unexpected response: %+v", resp)
+	}
+
+	// The returned revision is enough for a follow-up update, without reading the document again.
+	resp, err = db.UpdateDoc(context.Background(), "doc1", map[string]interface{}{"_id": "doc1", "_rev": resp.Rev, "name": "John"})
+	if err != nil {
+		t.Fatalf("Unexpected error updating document: %v", err)
+	}
+	if resp.Rev != "2-x" {
+		t.Errorf("Expected revision 2-x, got %s", resp.Rev)
+	}
+}
+
+func TestCreateDocWithID(t *testing.T) {
+	fake := &fakeDocServer{docs: map[string]map[string]any{
+		"existing": {"_id": "existing", "_rev": "1-abc"},
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name          string
+		id            string
+		expectedError error
+	}{
+		{name: "new document", id: "tenant:42:invoice:1001"},
+		{name: "existing document", id: "existing", expectedError: ErrConflict},
+		{name: "missing id", id: "", expectedError: ErrMissingID},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := db.CreateDocWithID(context.Background(), tc.id, map[string]string{"number": "1001"})
+			if tc.expectedError != nil {
+				if !errors.Is(err, tc.expectedError) {
+					t.Errorf("Expected %v, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resp.ID != tc.id || fake.docs[tc.id]["number"] != "1001" {
+				t.Errorf("Expected the document to be stored under %s, got %+v", tc.id, resp)
+			}
+		})
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	testCases := []struct {
+		name        string
+		docs        map[string]map[string]any
+		doc         any
+		expectedRev string
+		puts        int
+	}{
+		{name: "new document", docs: map[string]map[string]any{}, doc: map[string]any{"name": "John"}, expectedRev: "1-x", puts: 1},
+		{
+			name:        "existing document without rev",
+			docs:        map[string]map[string]any{"doc1": {"_id": "doc1", "_rev": "7-old", "name": "Jane"}},
+			doc:         struct{ Name string }{Name: "John"},
+			expectedRev: "1-x",
+			puts:        1,
+		},
+		{
+			name:        "existing document with current rev",
+			docs:        map[string]map[string]any{"doc1": {"_id": "doc1", "_rev": "7-old"}},
+			doc:         map[string]any{"_rev": "7-old", "name": "John"},
+			expectedRev: "1-x",
+			puts:        1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeDocServer{docs: tc.docs}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			resp, err := db.Upsert(context.Background(), "doc1", tc.doc)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resp.Rev != tc.expectedRev || len(fake.puts) != tc.puts {
+				t.Errorf("Expected rev %s after %d writes, got %s after %d", tc.expectedRev, tc.puts, resp.Rev, len(fake.puts))
+			}
+			if fake.docs["doc1"]["_id"] != "doc1" {
+				t.Errorf("Expected the document to be stored with its ID, got %v", fake.docs["doc1"])
+			}
+		})
+	}
+}
+
+func TestUpsertGivesUp(t *testing.T) {
+	testCases := []struct {
+		name         string
+		opts         []Option
+		expectedPuts int
+	}{
+		{name: "default attempts", expectedPuts: defaultUpsertAttempts},
+		{name: "custom attempts", opts: []Option{WithUpsertAttempts(2)}, expectedPuts: 2},
+		{name: "single attempt", opts: []Option{WithUpsertAttempts(0)}, expectedPuts: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			puts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `"9-busy"`)
+				if r.Method == http.MethodPut {
+					puts++
+					w.WriteHeader(http.StatusConflict)
+					w.Write([]byte(`{"error":"conflict","reason":"Document update conflict."}`))
+				}
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second, tc.opts...), dbName: "test"}
+			_, err := db.Upsert(context.Background(), "doc1", map[string]any{"name": "John"})
+			var conflict *ConflictError
+			if !errors.Is(err, ErrConflict) || !errors.As(err, &conflict) {
+				t.Fatalf("Expected a *ConflictError, got %v", err)
+			}
+			if conflict.CurrentRev != "9-busy" {
+				t.Errorf("Expected the current revision 9-busy, got %q", conflict.CurrentRev)
+			}
+			if puts != tc.expectedPuts {
+				t.Errorf("Expected %d writes, got %d", tc.expectedPuts, puts)
+			}
+		})
+	}
+}
+
+func TestDeleteDocRev(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rev           string
+		expectedError error
+	}{
+		{name: "current rev", rev: "2-current"},
+		{name: "stale rev", rev: "1-stale", expectedError: ErrConflict},
+		{name: "missing rev", rev: "", expectedError: ErrMissingRev},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeDocServer{docs: map[string]map[string]any{
+				"doc1": {"_id": "doc1", "_rev": "2-current"},
+			}}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			err := db.DeleteDocRev(context.Background(), "doc1", tc.rev)
+			if tc.expectedError != nil {
+				if !errors.Is(err, tc.expectedError) {
+					t.Errorf("Expected %v, got %v", tc.expectedError, err)
+				}
+				if _, exists := fake.docs["doc1"]; !exists {
+					t.Errorf("Expected the document to be kept")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, exists := fake.docs["doc1"]; exists {
+				t.Errorf("Expected the document to be deleted")
+			}
+		})
+	}
+}
+
+func TestUpdateDocWritesBackRev(t *testing.T) {
+	fake := &fakeDocServer{docs: map[string]map[string]any{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	type person struct {
+		Document
+		Name string `json:"name"`
+	}
+	doc := &person{Document: Document{ID: "john"}, Name: "John"}
+	if _, err := db.CreateDocWithID(context.Background(), "john", doc); err != nil {
+		t.Fatalf("Unexpected error creating document: %v", err)
+	}
+
+	// Each write picks up the revision of the previous one, so none of them conflicts.
+	for i := 0; i < 3; i++ {
+		doc.Name = fmt.Sprintf("John %d", i)
+		if _, err := db.UpdateDoc(context.Background(), "john", doc); err != nil {
+			t.Fatalf("Unexpected error on update %d: %v", i, err)
+		}
+	}
+	if doc.Rev != "4-x" {
+		t.Errorf("Expected revision 4-x, got %s", doc.Rev)
+	}
+}
+
+func TestCopyDoc(t *testing.T) {
+	testCases := []struct {
+		name                string
+		targetID            string
+		targetRev           string
+		expectedDestination string
+	}{
+		{name: "new target", targetID: "invoice:1001", targetRev: "", expectedDestination: "invoice:1001"},
+		{name: "existing target", targetID: "invoice:1001", targetRev: "3-abc", expectedDestination: "invoice:1001?rev=3-abc"},
+		{name: "target with reserved characters", targetID: "invoices/2024 #1", targetRev: "", expectedDestination: "invoices%2F2024%20%231"},
+		{name: "design document target", targetID: "_design/copy", targetRev: "1-d", expectedDestination: "_design/copy?rev=1-d"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "COPY" || r.URL.Path != "/test/template:invoice" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if destination := r.Header.Get("Destination"); destination != tc.expectedDestination {
+					t.Errorf("Expected destination %q, got %q", tc.expectedDestination, destination)
+				}
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"ok":true,"id":"invoice:1001","rev":"1-copy"}`))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			resp, err := db.CopyDoc(context.Background(), "template:invoice", tc.targetID, tc.targetRev)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resp.ID != "invoice:1001" || resp.Rev != "1-copy" {
+				t.Errorf("Unexpected response: %+v", resp)
+			}
+		})
+	}
+}
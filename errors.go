@@ -1,11 +1,123 @@
 package couchdb
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
-	ErrNotFound = errors.New("not found")
+	ErrBadRequest         = errors.New("bad request")         // 400: invalid request body or parameters
+	ErrUnauthorized       = errors.New("unauthorized")        // 401: missing or invalid credentials
+	ErrForbidden          = errors.New("forbidden")           // 403: the user lacks the required permissions
+	ErrNotFound           = errors.New("not found")           // 404: the database, document or endpoint doesn't exist
+	ErrConflict           = errors.New("conflict")            // 409: the document was updated by someone else
+	ErrPreconditionFailed = errors.New("precondition failed") // 412: e.g. the database already exists
+	ErrRequestTooLarge    = errors.New("request too large")   // 413: the document or request exceeds the server limits
+	ErrServerError        = errors.New("server error")        // 5xx: the server failed to process the request
+
+	// ErrCircuitOpen is returned without contacting the server while the circuit breaker set with
+	// WithCircuitBreaker is open.
+	ErrCircuitOpen = errors.New("circuit breaker open")
+
+	// ErrResponseTooLarge is returned when a response body exceeds the limit set with WithMaxResponseSize.
+	// Such requests are not retried, since the server would send the same response again.
+	ErrResponseTooLarge = errors.New("response too large")
 
 	codeToError = map[int]error{
+		400: ErrBadRequest,
+		401: ErrUnauthorized,
+		403: ErrForbidden,
 		404: ErrNotFound,
+		409: ErrConflict,
+		412: ErrPreconditionFailed,
+		413: ErrRequestTooLarge,
 	}
 )
+
+// statusError returns the sentinel error for a failed response status code, or nil if there is none.
+func statusError(statusCode int) error {
+	if err, ok := codeToError[statusCode]; ok {
+		return err
+	}
+	if statusCode >= 500 {
+		return ErrServerError
+	}
+	return nil
+}
+
+// Error is a failed response from CouchDB, with the details decoded from its {"error": ..., "reason": ...} body.
+// It wraps the sentinel error of its status code, if any, so both errors.As and errors.Is can be used:
+//
+//	var couchErr *couchdb.Error
+//	if errors.As(err, &couchErr) && couchErr.ErrorName == "forbidden" {
+//	    log.Printf("Rejected by validation function: %s", couchErr.Reason)
+//	}
+type Error struct {
+	StatusCode int    // HTTP status code of the response
+	ErrorName  string // Error name reported by CouchDB, e.g. "conflict" or "not_found"; empty if the body wasn't JSON
+	Reason     string // Human-readable reason reported by CouchDB, or the raw body if it wasn't JSON
+
+	action string // Operation that failed, e.g. "error getting doc"
+}
+
+func (e *Error) Error() string {
+	if e.ErrorName == "" {
+		return fmt.Sprintf("%s: %d - %s", e.action, e.StatusCode, e.Reason)
+	}
+	return fmt.Sprintf("%s: %d %s: %s", e.action, e.StatusCode, e.ErrorName, e.Reason)
+}
+
+// Unwrap returns the sentinel error of the status code, such as ErrNotFound, or nil if there is none.
+func (e *Error) Unwrap() error {
+	return statusError(e.StatusCode)
+}
+
+// ConflictError is returned when a write is rejected with 409 Conflict because the document has a newer revision
+// than the one sent. It wraps the *Error of the response, so errors.Is(err, ErrConflict) holds.
+//
+// Example:
+//
+//	var conflict *couchdb.ConflictError
+//	if errors.As(err, &conflict) && conflict.CurrentRev != "" {
+//	    doc.Rev = conflict.CurrentRev
+//	    _, err = db.UpdateDoc(ctx, doc.ID, merge(doc))
+//	}
+type ConflictError struct {
+	Err        *Error // The response of the rejected write
+	CurrentRev string // Current revision of the document on the server; empty if it couldn't be determined
+}
+
+func (e *ConflictError) Error() string {
+	if e.CurrentRev == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s (current rev %s)", e.Err.Error(), e.CurrentRev)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// responseError returns the *Error for an unexpected response to the operation described by action,
+// e.g. "error getting doc".
+func responseError(action string, statusCode int, body []byte) error {
+	return newError(action, statusCode, body)
+}
+
+// newError decodes the body of a failed response into an *Error.
+func newError(action string, statusCode int, body []byte) *Error {
+	couchErr := &Error{StatusCode: statusCode, action: action}
+
+	var decoded struct {
+		Error  string `json:"error"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(body, &decoded); err == nil && decoded.Error != "" {
+		couchErr.ErrorName, couchErr.Reason = decoded.Error, decoded.Reason
+	} else {
+		couchErr.Reason = strings.TrimSpace(string(body))
+	}
+	return couchErr
+}
@@ -0,0 +1,130 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponseErrors(t *testing.T) {
+	var status int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"error":"failure","reason":"it failed"}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		status   int
+		expected error
+	}{
+		{status: 400, expected: ErrBadRequest},
+		{status: 401, expected: ErrUnauthorized},
+		{status: 403, expected: ErrForbidden},
+		{status: 404, expected: ErrNotFound},
+		{status: 409, expected: ErrConflict},
+		{status: 412, expected: ErrPreconditionFailed},
+		{status: 413, expected: ErrRequestTooLarge},
+		{status: 500, expected: ErrServerError},
+		{status: 503, expected: ErrServerError},
+		{status: 418, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			status = tc.status
+			var doc Document
+			_, updateErr := db.UpdateDoc(context.Background(), "doc1", map[string]interface{}{"_id": "doc1", "_rev": "1-abc"})
+			operations := map[string]error{
+				"GetDoc":    db.GetDoc(context.Background(), "doc1", &doc),
+				"UpdateDoc": updateErr,
+				"Find":      db.Find(context.Background(), FindQuery{Selector: map[string]any{}}, &struct{}{}),
+			}
+			for name, err := range operations {
+				if err == nil {
+					t.Fatalf("%s: expected an error, got nil", name)
+				}
+				for _, sentinel := range codeToError {
+					if errors.Is(err, sentinel) != (sentinel == tc.expected) {
+						t.Errorf("%s: unexpected errors.Is(%v) for %v", name, sentinel, err)
+					}
+				}
+				if errors.Is(err, ErrServerError) != (tc.expected == ErrServerError) {
+					t.Errorf("%s: unexpected errors.Is(ErrServerError) for %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseErrorDetails(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statusCode       int
+		body             string
+		expectedName     string
+		expectedReason   string
+		expectedMessage  string
+		expectedSentinel error
+	}{
+		{
+			name:             "couchdb error body",
+			statusCode:       409,
+			body:             `{"error":"conflict","reason":"Document update conflict."}`,
+			expectedName:     "conflict",
+			expectedReason:   "Document update conflict.",
+			expectedMessage:  "error updating doc: 409 conflict: Document update conflict.",
+			expectedSentinel: ErrConflict,
+		},
+		{
+			name:             "validation failure",
+			statusCode:       403,
+			body:             `{"error":"forbidden","reason":"Only admins may delete."}`,
+			expectedName:     "forbidden",
+			expectedReason:   "Only admins may delete.",
+			expectedMessage:  "error updating doc: 403 forbidden: Only admins may delete.",
+			expectedSentinel: ErrForbidden,
+		},
+		{
+			name:             "non JSON body",
+			statusCode:       502,
+			body:             "Bad Gateway\n",
+			expectedReason:   "Bad Gateway",
+			expectedMessage:  "error updating doc: 502 - Bad Gateway",
+			expectedSentinel: ErrServerError,
+		},
+		{
+			name:            "status without sentinel",
+			statusCode:      418,
+			body:            `{"error":"teapot","reason":"short and stout"}`,
+			expectedName:    "teapot",
+			expectedReason:  "short and stout",
+			expectedMessage: "error updating doc: 418 teapot: short and stout",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := responseError("error updating doc", tc.statusCode, []byte(tc.body))
+
+			var couchErr *Error
+			if !errors.As(err, &couchErr) {
+				t.Fatalf("Expected a *Error, got %T", err)
+			}
+			if couchErr.StatusCode != tc.statusCode || couchErr.ErrorName != tc.expectedName || couchErr.Reason != tc.expectedReason {
+				t.Errorf("Unexpected error details: %+v", couchErr)
+			}
+			if err.Error() != tc.expectedMessage {
+				t.Errorf("Expected message %q, got %q", tc.expectedMessage, err.Error())
+			}
+			if errors.Unwrap(err) != tc.expectedSentinel {
+				t.Errorf("Expected sentinel %v, got %v", tc.expectedSentinel, errors.Unwrap(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultIAMTokenURL is the endpoint of the IBM Cloud IAM service exchanging API keys for access tokens.
+const defaultIAMTokenURL = "https://iam.cloud.ibm.com/identity/token"
+
+// iamRefreshRatio is the fraction of the lifetime of an IAM token after which it is refreshed, as recommended by IAM.
+const iamRefreshRatio = 0.8
+
+// iamMinTokenLifetime is the lifetime assumed for IAM tokens reported to expire sooner, e.g. with an expires_in
+// of 0, so that a token is not exchanged again before every request.
+const iamMinTokenLifetime = time.Minute
+
+// WithIAMAuth authenticates with IBM Cloud IAM, as used by Cloudant: the API key is exchanged for an access token,
+// sent as a bearer token, which is refreshed once 80% of its lifetime has elapsed, or when the server rejects it.
+//
+// An empty tokenURL uses the production IAM endpoint. The credentials passed to GetInstance are ignored.
+//
+// Example:
+//
+//	cs := couchdb.GetInstance("https://account.cloudantnosqldb.appdomain.cloud", "", "",
+//	    couchdb.WithIAMAuth(os.Getenv("CLOUDANT_APIKEY"), ""))
+func WithIAMAuth(apiKey, tokenURL string) Option {
+	if tokenURL == "" {
+		tokenURL = defaultIAMTokenURL
+	}
+	return func(c *CustomHTTPClient) {
+		c.auth = &iamAuth{client: c, apiKey: apiKey, tokenURL: tokenURL}
+	}
+}
+
+// iamAuth authenticates requests with an IAM access token, obtained and refreshed on demand.
+type iamAuth struct {
+	client   *CustomHTTPClient
+	apiKey   string
+	tokenURL string
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+func (a *iamAuth) Authenticate(req *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == "" || !time.Now().Before(a.refreshAt) {
+		if err := a.refresh(req.Context()); err != nil {
+			return err
+		}
+	}
+
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+func (a *iamAuth) Refresh(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.refresh(ctx)
+}
+
+// refresh exchanges the API key for a new access token. a.mu must be held.
+func (a *iamAuth) refresh(ctx context.Context) error {
+	c := a.client
+	form := url.Values{}
+	form.Set("grant_type", "urn:ibm:params:oauth:grant-type:apikey")
+	form.Set("apikey", a.apiKey)
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", mediaTypeJSON)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error getting IAM token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error getting IAM token: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return responseError("error getting IAM token", resp.StatusCode, respBody)
+	}
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"` // Lifetime of the token, in seconds
+	}
+	if err := json.Unmarshal(respBody, &tokenResponse); err != nil {
+		return fmt.Errorf("error unmarshalling IAM token response: %w", err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return fmt.Errorf("error getting IAM token: no access token in response")
+	}
+
+	lifetime := max(time.Duration(tokenResponse.ExpiresIn)*time.Second, iamMinTokenLifetime)
+	a.token = tokenResponse.AccessToken
+	a.refreshAt = time.Now().Add(time.Duration(float64(lifetime) * iamRefreshRatio))
+	return nil
+}
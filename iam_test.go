@@ -0,0 +1,111 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIAMAuth(t *testing.T) {
+	var issued int
+	var expiresIn int
+	iam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("apikey") != "key" || r.FormValue("grant_type") != "urn:ibm:params:oauth:grant-type:apikey" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		issued++
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":%d}`, issued, expiresIn)
+	}))
+	defer iam.Close()
+
+	var authorizations []string
+	couch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") == "Bearer token-1" && r.URL.Path == "/revoked" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer couch.Close()
+
+	testCases := []struct {
+		name      string
+		expiresIn int
+		expire    bool // Whether the token expires after the first request
+		paths     []string
+		expected  []string
+	}{
+		{
+			name:      "token is reused while valid",
+			expiresIn: 3600,
+			paths:     []string{"a", "b"},
+			expected:  []string{"Bearer token-1", "Bearer token-1"},
+		},
+		{
+			name:      "token without lifetime is kept for the minimum lifetime",
+			expiresIn: 0,
+			paths:     []string{"a", "b"},
+			expected:  []string{"Bearer token-1", "Bearer token-1"},
+		},
+		{
+			name:      "expired token is refreshed",
+			expiresIn: 3600,
+			expire:    true,
+			paths:     []string{"a", "b"},
+			expected:  []string{"Bearer token-1", "Bearer token-2"},
+		},
+		{
+			name:      "rejected token is refreshed and the request retried",
+			expiresIn: 3600,
+			paths:     []string{"revoked"},
+			expected:  []string{"Bearer token-1", "Bearer token-2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			issued, expiresIn, authorizations = 0, tc.expiresIn, nil
+			client := NewCustomHTTPClient(couch.URL+"/", 1, time.Millisecond, time.Second)
+			WithIAMAuth("key", iam.URL)(client)
+
+			for _, path := range tc.paths {
+				code, _, err := client.Get(context.Background(), path)
+				if err != nil || code != http.StatusOK {
+					t.Fatalf("Unexpected response: %d, %v", code, err)
+				}
+				if tc.expire {
+					client.auth.(*iamAuth).refreshAt = time.Now()
+				}
+			}
+			if fmt.Sprint(authorizations) != fmt.Sprint(tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, authorizations)
+			}
+		})
+	}
+}
+
+func TestIAMAuthTokenError(t *testing.T) {
+	iam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"errorCode":"BXNIM0415E","errorMessage":"Provided API key could not be found."}`))
+	}))
+	defer iam.Close()
+
+	couch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request without a token")
+	}))
+	defer couch.Close()
+
+	client := NewCustomHTTPClient(couch.URL+"/", 1, time.Millisecond, time.Second)
+	WithIAMAuth("revoked", iam.URL)(client)
+
+	if _, _, err := client.Get(context.Background(), "test"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// DBInfo is the information about a database returned by its root endpoint.
+type DBInfo struct {
+	DBName            string    `json:"db_name"`
+	DocCount          int64     `json:"doc_count"`     // Number of documents, excluding deleted ones
+	DocDelCount       int64     `json:"doc_del_count"` // Number of deleted documents
+	UpdateSeq         Seq       `json:"update_seq"`
+	PurgeSeq          Seq       `json:"purge_seq"`
+	CompactRunning    bool      `json:"compact_running"`
+	Sizes             DBSizes   `json:"sizes"`
+	Cluster           DBCluster `json:"cluster"`
+	Props             DBProps   `json:"props"`
+	InstanceStartTime string    `json:"instance_start_time"`
+	DiskFormatVersion int       `json:"disk_format_version"`
+}
+
+// DBSizes are the sizes of a database, in bytes.
+type DBSizes struct {
+	File     int64 `json:"file"`     // Size of the database files on disk
+	External int64 `json:"external"` // Uncompressed size of the live data
+	Active   int64 `json:"active"`   // Size of the live data on disk; File minus Active is what compaction can reclaim
+}
+
+// DBCluster is the sharding and quorum configuration of a database.
+type DBCluster struct {
+	Q int `json:"q"` // Number of shards
+	N int `json:"n"` // Number of replicas of each shard
+	R int `json:"r"` // Read quorum
+	W int `json:"w"` // Write quorum
+}
+
+// DBProps are the properties a database was created with.
+type DBProps struct {
+	Partitioned bool `json:"partitioned,omitempty"`
+}
+
+// Info returns the information about the database, such as its document count, sizes and cluster configuration.
+//
+// Example:
+//
+//	info, err := db.Info(ctx)
+//	if err != nil {
+//	    log.Fatalf("Error getting database info: %v", err)
+//	}
+//	fmt.Printf("%d documents, %d bytes reclaimable\n", info.DocCount, info.Sizes.File-info.Sizes.Active)
+func (db *Database) Info(ctx context.Context, opts ...RequestOption) (*DBInfo, error) {
+	respCode, respBody, err := db.httpClient.Get(ctx, db.dbName, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting database info: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting database info", respCode, respBody)
+	}
+
+	var info DBInfo
+	err = json.Unmarshal(respBody, &info)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling database info: %w", err)
+	}
+
+	return &info, nil
+}
+
+// DBsInfoResult is the information about one of the databases requested from DBsInfo.
+// Info is nil and Error is set if the database doesn't exist.
+type DBsInfoResult struct {
+	Key   string  `json:"key"` // Name of the database
+	Info  *DBInfo `json:"info,omitempty"`
+	Error string  `json:"error,omitempty"`
+}
+
+// DBsInfo returns the information about several databases in a single request to the _dbs_info endpoint,
+// e.g. to collect the stats of every tenant database without one request per database.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - names: The names of the databases.
+//
+// Returns:
+//   - []DBsInfoResult: One result per database, in the same order as names.
+//   - error: An error if the request as a whole failed.
+func (c *CouchService) DBsInfo(ctx context.Context, names []string) ([]DBsInfoResult, error) {
+	body := map[string]any{"keys": names}
+
+	respCode, respBody, err := c.httpClient.Post(ctx, "_dbs_info", body)
+	if err != nil {
+		return nil, fmt.Errorf("error getting databases info: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting databases info", respCode, respBody)
+	}
+
+	var results []DBsInfoResult
+	err = json.Unmarshal(respBody, &results)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling databases info: %w", err)
+	}
+
+	return results, nil
+}
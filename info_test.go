@@ -0,0 +1,98 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDBInfoUnmarshal(t *testing.T) {
+	body := `{
+		"db_name": "test",
+		"doc_count": 12,
+		"doc_del_count": 3,
+		"update_seq": "15-g1AAAA",
+		"purge_seq": 0,
+		"compact_running": false,
+		"sizes": {"file": 4096, "external": 1024, "active": 2048},
+		"cluster": {"q": 2, "n": 3, "r": 2, "w": 2},
+		"props": {"partitioned": true},
+		"instance_start_time": "0",
+		"disk_format_version": 8
+	}`
+
+	var info DBInfo
+	if err := json.Unmarshal([]byte(body), &info); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := DBInfo{
+		DBName:            "test",
+		DocCount:          12,
+		DocDelCount:       3,
+		UpdateSeq:         "15-g1AAAA",
+		PurgeSeq:          "0",
+		Sizes:             DBSizes{File: 4096, External: 1024, Active: 2048},
+		Cluster:           DBCluster{Q: 2, N: 3, R: 2, W: 2},
+		Props:             DBProps{Partitioned: true},
+		InstanceStartTime: "0",
+		DiskFormatVersion: 8,
+	}
+	if info != expected {
+		t.Errorf("Expected %+v, got %+v", expected, info)
+	}
+}
+
+func TestDBsInfo(t *testing.T) {
+	testCases := []struct {
+		name            string
+		statusCode      int
+		body            string
+		expectedResults []DBsInfoResult
+		expectedError   error
+	}{
+		{
+			name:       "existing and missing databases",
+			statusCode: 200,
+			body:       `[{"key":"tenant-1","info":{"db_name":"tenant-1","doc_count":12,"sizes":{"file":4096,"external":900,"active":2048}}},{"key":"tenant-2","error":"not_found"}]`,
+			expectedResults: []DBsInfoResult{
+				{Key: "tenant-1", Info: &DBInfo{DBName: "tenant-1", DocCount: 12, Sizes: DBSizes{File: 4096, External: 900, Active: 2048}}},
+				{Key: "tenant-2", Error: "not_found"},
+			},
+		},
+		{name: "too many databases", statusCode: 400, body: `{"error":"bad_request","reason":"You have exceeded the maximum number of keys."}`, expectedError: ErrBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/_dbs_info" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				var body struct {
+					Keys []string `json:"keys"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !reflect.DeepEqual(body.Keys, []string{"tenant-1", "tenant-2"}) {
+					t.Errorf("Unexpected body: %v, %v", body, err)
+				}
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+			results, err := cs.DBsInfo(context.Background(), []string{"tenant-1", "tenant-2"})
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if !reflect.DeepEqual(results, tc.expectedResults) {
+				t.Errorf("Expected results %+v, got %+v", tc.expectedResults, results)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package couchdb
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the client to perSecond requests per second on average, with bursts of up to burst requests,
+// so that a spike of application traffic can't overwhelm a small CouchDB node or exceed the request quota of a
+// Cloudant plan. Requests over the limit wait for their turn, for as long as their context allows.
+// Every attempt counts, including retries. A non-positive perSecond disables the limit, which is the default.
+//
+// The limit is shared by every database handle obtained from the service.
+//
+// Example:
+//
+//	// Stay below the 20 reads per second of a Cloudant Lite plan.
+//	cs, err := couchdb.New(url, couchdb.WithRateLimit(20, 5))
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(c *CustomHTTPClient) {
+		if perSecond <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = newRateLimiter(perSecond, max(burst, 1))
+	}
+}
+
+// WithMaxInFlight limits the number of requests the client sends at the same time to maxInFlight.
+// Further requests wait for one of them to complete, for as long as their context allows. Streamed responses,
+// such as those of ViewRows or Changes, only count until their headers are received, so that long-lived feeds
+// don't hold on to a slot. A non-positive maxInFlight disables the limit, which is the default.
+//
+// The limit is shared by every database handle obtained from the service.
+func WithMaxInFlight(maxInFlight int) Option {
+	return func(c *CustomHTTPClient) {
+		if maxInFlight <= 0 {
+			c.inFlight = nil
+			return
+		}
+		c.inFlight = make(chan struct{}, maxInFlight)
+	}
+}
+
+// acquire waits until the limits of the client let an attempt be sent. The returned function must be called
+// once the attempt is done, to free its in-flight slot.
+func (c *CustomHTTPClient) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if c.inFlight != nil {
+		select {
+		case c.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		release = func() { <-c.inFlight }
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// rateLimiter is a token bucket: it holds up to burst tokens, refilled at rate tokens per second,
+// and every attempt takes one.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64   // Tokens left; negative when attempts are waiting for tokens to be refilled
+	last   time.Time // When tokens was last updated
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait takes a token, waiting until it is refilled if there is none left.
+// If ctx is done first, the token is given back and the context error returned.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		l.unreserve()
+		return err
+	}
+	return nil
+}
+
+// reserve takes a token and returns how long to wait until it is available.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// unreserve gives back a token taken by reserve that was not used.
+func (l *rateLimiter) unreserve() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.burst, l.tokens+1)
+}
@@ -0,0 +1,112 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	limiter := newRateLimiter(10, 2)
+
+	testCases := []struct {
+		name     string
+		expected time.Duration
+	}{
+		{name: "first token of the burst", expected: 0},
+		{name: "second token of the burst", expected: 0},
+		{name: "first token over the burst", expected: 100 * time.Millisecond},
+		{name: "second token over the burst", expected: 200 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delay := limiter.reserve()
+			if delay > tc.expected || delay < tc.expected-10*time.Millisecond {
+				t.Errorf("Expected a delay of about %v, got %v", tc.expected, delay)
+			}
+		})
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second, WithRateLimit(20, 1))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, _, err := client.Get(context.Background(), "test"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected 3 requests at 20 per second to take at least 100ms, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	client.Get(ctx, "test")
+	if _, _, err := client.Get(ctx, "test"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the wait to be cut by the context, got %v", err)
+	}
+}
+
+func TestWithMaxInFlight(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, maxSeen int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxSeen = max(maxSeen, inFlight)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name    string
+		opts    []Option
+		atLeast int
+		atMost  int
+	}{
+		{name: "no limit", atLeast: 3, atMost: 6},
+		{name: "two in flight", opts: []Option{WithMaxInFlight(2)}, atLeast: 1, atMost: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			maxSeen = 0
+			client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second, tc.opts...)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 6; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, _, err := client.Get(context.Background(), "test"); err != nil {
+						t.Errorf("Unexpected error: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if maxSeen < tc.atLeast || maxSeen > tc.atMost {
+				t.Errorf("Expected between %d and %d requests in flight, got %d", tc.atLeast, tc.atMost, maxSeen)
+			}
+		})
+	}
+}
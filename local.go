@@ -0,0 +1,82 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// localDocPrefix is the ID prefix of local documents.
+const localDocPrefix = "_local/"
+
+// localDocID returns the full ID of a local document, accepting IDs given with or without the _local/ prefix.
+func localDocID(id string) string {
+	return localDocPrefix + strings.TrimPrefix(id, localDocPrefix)
+}
+
+// GetLocalDoc retrieves a local document, as GetDoc does for regular documents.
+//
+// Local documents are never replicated, nor included in views, _all_docs or _changes, which makes them suitable
+// for replication checkpoints and per-node metadata. Their revisions are "0-1", "0-2" and so on, and no history is kept.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - id: The ID of the local document, with or without the _local/ prefix.
+//   - doc: A pointer to a struct (or map[string]interface{}) where the document data will be populated.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - error: ErrNotFound if the local document doesn't exist, or any other error encountered.
+//
+// Example:
+//
+//	var checkpoint Checkpoint
+//	err := db.GetLocalDoc(ctx, "sync-checkpoint", &checkpoint)
+//	if errors.Is(err, couchdb.ErrNotFound) {
+//	    checkpoint = Checkpoint{Since: "0"}
+//	}
+func (db *Database) GetLocalDoc(ctx context.Context, id string, doc any, opts ...RequestOption) error {
+	if !isValidParam(doc) {
+		return fmt.Errorf("doc parameter must be a pointer to a struct")
+	}
+
+	return db.getDoc(ctx, localDocID(id), GetDocOptions{}, doc, opts...)
+}
+
+// PutLocalDoc creates or updates a local document, as UpdateDoc does for regular documents: updates must send
+// the current revision of the local document, which is written back to doc if it is a map or a pointer to a struct
+// embedding Document.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - id: The ID of the local document, with or without the _local/ prefix.
+//   - doc: The content of the local document.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - *UpdateDocResponse: The full ID and new revision of the local document.
+//   - error: An error, if any, encountered while writing the local document.
+//     A *ConflictError is returned if the revision sent is not the current one.
+func (db *Database) PutLocalDoc(ctx context.Context, id string, doc any, opts ...RequestOption) (*UpdateDocResponse, error) {
+	resp, err := db.putDoc(ctx, localDocID(id), doc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	setDocMeta(doc, "", resp.Rev)
+	return resp, nil
+}
+
+// DeleteLocalDoc deletes a local document.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - id: The ID of the local document, with or without the _local/ prefix.
+//   - rev: The current revision of the local document.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - error: An error, if any, encountered while deleting the local document.
+func (db *Database) DeleteLocalDoc(ctx context.Context, id, rev string, opts ...RequestOption) error {
+	return db.DeleteDocRev(ctx, localDocID(id), rev, opts...)
+}
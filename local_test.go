@@ -0,0 +1,55 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLocalDocs(t *testing.T) {
+	fake := &fakeDocServer{docs: map[string]map[string]any{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+	ctx := context.Background()
+
+	type checkpoint struct {
+		Document
+		Since string `json:"since"`
+	}
+
+	doc := &checkpoint{Since: "10-abc"}
+	if _, err := db.PutLocalDoc(ctx, "sync", doc); err != nil {
+		t.Fatalf("Unexpected error creating local doc: %v", err)
+	}
+	if _, ok := fake.docs["_local/sync"]; !ok {
+		t.Fatalf("Expected the doc to be stored under _local/sync, got %v", fake.docs)
+	}
+
+	// The revision written back allows updating the local document right away.
+	doc.Since = "20-def"
+	if _, err := db.PutLocalDoc(ctx, "_local/sync", doc); err != nil {
+		t.Fatalf("Unexpected error updating local doc: %v", err)
+	}
+
+	var read checkpoint
+	if err := db.GetLocalDoc(ctx, "sync", &read); err != nil {
+		t.Fatalf("Unexpected error getting local doc: %v", err)
+	}
+	if read.Since != "20-def" || read.Rev != doc.Rev {
+		t.Errorf("Expected since 20-def at rev %s, got %+v", doc.Rev, read)
+	}
+
+	if err := db.DeleteLocalDoc(ctx, "sync", "1-stale"); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected ErrConflict deleting a stale revision, got %v", err)
+	}
+	if err := db.DeleteLocalDoc(ctx, "sync", doc.Rev); err != nil {
+		t.Fatalf("Unexpected error deleting local doc: %v", err)
+	}
+	if err := db.GetLocalDoc(ctx, "sync", &read); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after deletion, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ViewCleanup removes the view index files that are no longer needed, e.g. those of deleted design documents
+// or of views whose definition changed. CouchDB runs the cleanup in the background.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - An error, if any, encountered while requesting the cleanup.
+//     If the cleanup is started, it returns nil.
+func (db *Database) ViewCleanup(ctx context.Context, opts ...RequestOption) error {
+	respCode, respBody, err := db.httpClient.Post(ctx, fmt.Sprintf("%s/_view_cleanup", db.dbName), nil, opts...)
+	if err != nil {
+		return fmt.Errorf("error requesting view cleanup: %w", err)
+	}
+
+	if respCode != 202 {
+		return responseError("error requesting view cleanup", respCode, respBody)
+	}
+
+	return nil
+}
+
+// Compact starts the compaction of the database file, which reclaims the space used by old document revisions.
+// CouchDB compacts in the background; IsCompacting reports when it is done.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - An error, if any, encountered while requesting the compaction.
+//     If the compaction is started, it returns nil.
+func (db *Database) Compact(ctx context.Context, opts ...RequestOption) error {
+	return db.compact(ctx, fmt.Sprintf("%s/_compact", db.dbName), opts...)
+}
+
+// CompactView starts the compaction of the view indexes of a design document.
+// CouchDB compacts in the background; IsViewCompacting reports when it is done.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - ddoc: The name of the design document, with or without the "_design/" prefix.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - An error, if any, encountered while requesting the compaction.
+//     If the compaction is started, it returns nil.
+func (db *Database) CompactView(ctx context.Context, ddoc string, opts ...RequestOption) error {
+	return db.compact(ctx, fmt.Sprintf("%s/_compact/%s", db.dbName, escapePathSegment(strings.TrimPrefix(ddoc, "_design/"))), opts...)
+}
+
+// compact requests a compaction through the given endpoint.
+func (db *Database) compact(ctx context.Context, endpoint string, opts ...RequestOption) error {
+	respCode, respBody, err := db.httpClient.Post(ctx, endpoint, nil, opts...)
+	if err != nil {
+		return fmt.Errorf("error requesting compaction: %w", err)
+	}
+
+	if respCode != 202 {
+		return responseError("error requesting compaction", respCode, respBody)
+	}
+
+	return nil
+}
+
+// IsCompacting reports whether the database file is being compacted, so maintenance jobs can poll
+// for the end of a compaction started with Compact.
+//
+// Example:
+//
+//	if err := db.Compact(ctx); err != nil {
+//	    log.Fatalf("Error compacting database: %v", err)
+//	}
+//	for {
+//	    compacting, err := db.IsCompacting(ctx)
+//	    if err != nil {
+//	        log.Fatalf("Error getting compaction status: %v", err)
+//	    }
+//	    if !compacting {
+//	        break
+//	    }
+//	    time.Sleep(10 * time.Second)
+//	}
+func (db *Database) IsCompacting(ctx context.Context, opts ...RequestOption) (bool, error) {
+	info, err := db.Info(ctx, opts...)
+	if err != nil {
+		return false, err
+	}
+	return info.CompactRunning, nil
+}
+
+// IsViewCompacting reports whether the view indexes of a design document are being compacted,
+// so maintenance jobs can poll for the end of a compaction started with CompactView.
+func (db *Database) IsViewCompacting(ctx context.Context, ddoc string, opts ...RequestOption) (bool, error) {
+	var status struct {
+		ViewIndex struct {
+			CompactRunning bool `json:"compact_running"`
+		} `json:"view_index"`
+	}
+	endpoint := designPath(db.dbName, ddoc, "_info")
+	if err := db.getCompactionStatus(ctx, endpoint, &status, opts...); err != nil {
+		return false, err
+	}
+	return status.ViewIndex.CompactRunning, nil
+}
+
+// getCompactionStatus reads the info endpoint of a design document into status.
+func (db *Database) getCompactionStatus(ctx context.Context, endpoint string, status any, opts ...RequestOption) error {
+	respCode, respBody, err := db.httpClient.Get(ctx, endpoint, opts...)
+	if err != nil {
+		return fmt.Errorf("error getting compaction status: %w", err)
+	}
+
+	if respCode != 200 {
+		return responseError("error getting compaction status", respCode, respBody)
+	}
+
+	err = json.Unmarshal(respBody, status)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling compaction status: %w", err)
+	}
+
+	return nil
+}
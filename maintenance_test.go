@@ -0,0 +1,81 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCompactionStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/test":
+			_, _ = w.Write([]byte(`{"db_name":"test","compact_running":true}`))
+		case "/test/_design/ddoc/_info":
+			_, _ = w.Write([]byte(`{"name":"ddoc","view_index":{"compact_running":false}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name     string
+		status   func(ctx context.Context) (bool, error)
+		expected bool
+	}{
+		{name: "database", status: func(ctx context.Context) (bool, error) { return db.IsCompacting(ctx) }, expected: true},
+		{name: "view", status: func(ctx context.Context) (bool, error) { return db.IsViewCompacting(ctx, "_design/ddoc") }, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			compacting, err := tc.status(context.Background())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if compacting != tc.expected {
+				t.Errorf("Expected compacting: %v, got %v", tc.expected, compacting)
+			}
+		})
+	}
+}
+
+func TestViewCleanup(t *testing.T) {
+	testCases := []struct {
+		name          string
+		statusCode    int
+		body          string
+		expectedError error
+	}{
+		{name: "cleanup started", statusCode: 202, body: `{"ok":true}`},
+		{name: "not an admin", statusCode: 401, body: `{"error":"unauthorized","reason":"You are not a server admin."}`, expectedError: ErrUnauthorized},
+		{name: "missing database", statusCode: 404, body: `{"error":"not_found","reason":"Database does not exist."}`, expectedError: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/test/_view_cleanup" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+					t.Errorf("Expected a JSON content type, got %q", contentType)
+				}
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			if err := db.ViewCleanup(context.Background()); !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected error %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// IndexDefinition describes the fields covered by a Mango index.
+type IndexDefinition struct {
+	// Fields lists the indexed fields, either as plain field names ("name") or as
+	// single-entry objects giving the sort direction (map[string]string{"name": "asc"}).
+	Fields []any `json:"fields"`
+	// PartialFilterSelector restricts the index to the documents matching this selector.
+	PartialFilterSelector map[string]any `json:"partial_filter_selector,omitempty"`
+}
+
+// Index is a Mango index to be created with CreateIndex.
+type Index struct {
+	Index       IndexDefinition `json:"index"`
+	DDoc        string          `json:"ddoc,omitempty"`        // Design document to store the index in; generated if empty
+	Name        string          `json:"name,omitempty"`        // Index name; generated if empty
+	Type        string          `json:"type,omitempty"`        // "json" (default) or "text"
+	Partitioned *bool           `json:"partitioned,omitempty"` // Whether the index is partitioned; defaults to the database setting
+}
+
+// CreateIndexResponse is the response returned by CouchDB when creating a Mango index.
+type CreateIndexResponse struct {
+	Result string `json:"result"` // "created", or "exists" if an identical index was already defined
+	ID     string `json:"id"`     // ID of the design document holding the index
+	Name   string `json:"name"`   // Name of the index
+}
+
+// IndexInfo describes an existing Mango index, as listed by ListIndexes.
+type IndexInfo struct {
+	DDoc        string          `json:"ddoc"` // Empty for the special _all_docs index
+	Name        string          `json:"name"`
+	Type        string          `json:"type"` // "special", "json" or "text"
+	Partitioned bool            `json:"partitioned"`
+	Def         IndexDefinition `json:"def"`
+}
+
+// CreateIndex creates a Mango index so that Find queries on the indexed fields don't require a full scan.
+//
+// Creating an index that already exists is not an error; the returned response then reports "exists" as Result.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - index: The index to create.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - *CreateIndexResponse: The design document and name of the index.
+//   - error: An error, if any, encountered while creating the index.
+//
+// Example:
+//
+//	resp, err := db.CreateIndex(ctx, couchdb.Index{
+//	    Index: couchdb.IndexDefinition{Fields: []any{"type", "created_at"}},
+//	    DDoc:  "by-type",
+//	    Name:  "type-created-at",
+//	})
+//	if err != nil {
+//	    log.Fatalf("Error creating index: %v", err)
+//	}
+func (db *Database) CreateIndex(ctx context.Context, index Index, opts ...RequestOption) (*CreateIndexResponse, error) {
+	respCode, respBody, err := db.httpClient.Post(ctx, fmt.Sprintf("%s/_index", db.dbName), index, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating index: %w", err)
+	}
+
+	if respCode != 200 && respCode != 201 {
+		return nil, responseError("error creating index", respCode, respBody)
+	}
+
+	var createIndexResponse CreateIndexResponse
+	err = json.Unmarshal(respBody, &createIndexResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling create index response: %w", err)
+	}
+
+	return &createIndexResponse, nil
+}
+
+// ListIndexes returns every Mango index defined in the database, including the special _all_docs index.
+func (db *Database) ListIndexes(ctx context.Context, opts ...RequestOption) ([]IndexInfo, error) {
+	respCode, respBody, err := db.httpClient.Get(ctx, fmt.Sprintf("%s/_index", db.dbName), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing indexes: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error listing indexes", respCode, respBody)
+	}
+
+	var listIndexesResponse struct {
+		TotalRows int         `json:"total_rows"`
+		Indexes   []IndexInfo `json:"indexes"`
+	}
+	err = json.Unmarshal(respBody, &listIndexesResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling list indexes response: %w", err)
+	}
+
+	return listIndexesResponse.Indexes, nil
+}
+
+// DeleteIndex deletes a JSON Mango index.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - ddoc: The design document holding the index, with or without the "_design/" prefix.
+//   - name: The name of the index.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - An error, if any, encountered while deleting the index.
+//     If the deletion is successful, it returns nil.
+func (db *Database) DeleteIndex(ctx context.Context, ddoc, name string, opts ...RequestOption) error {
+	ddoc = strings.TrimPrefix(ddoc, "_design/")
+
+	respCode, respBody, err := db.httpClient.Delete(ctx, fmt.Sprintf("%s/_index/%s/json/%s", db.dbName, escapePathSegment(ddoc), escapePathSegment(name)), opts...)
+	if err != nil {
+		return fmt.Errorf("error deleting index: %w", err)
+	}
+
+	if respCode != 200 {
+		return responseError("error deleting index", respCode, respBody)
+	}
+
+	return nil
+}
+
+// FindQuery is a Mango query, as sent to the _find and _explain endpoints.
+type FindQuery struct {
+	Selector       any      `json:"selector"`                  // Conditions the returned documents must match
+	Limit          int      `json:"limit,omitempty"`           // Maximum number of results; the server default is 25
+	Skip           int      `json:"skip,omitempty"`            // Number of results to skip
+	Sort           []any    `json:"sort,omitempty"`            // Field names or {"field": "asc"|"desc"} objects
+	Fields         []string `json:"fields,omitempty"`          // Fields to return; all fields if empty
+	UseIndex       any      `json:"use_index,omitempty"`       // Design document name, or [ddoc, index name] pair
+	Conflicts      bool     `json:"conflicts,omitempty"`       // Include the _conflicts field of each document
+	Bookmark       string   `json:"bookmark,omitempty"`        // Bookmark returned by a previous query, for pagination
+	Update         *bool    `json:"update,omitempty"`          // Whether to update the index before returning results
+	Stable         bool     `json:"stable,omitempty"`          // Whether to use the same set of shard replicas for every request
+	ExecutionStats bool     `json:"execution_stats,omitempty"` // Include execution statistics in the response
+}
+
+// FindResponse defines a struct to represent the response JSON object returned by a Mango query.
+// It can be used as the resultVar of Find when the documents only need to be decoded later.
+type FindResponse struct {
+	Docs           []json.RawMessage `json:"docs"`
+	Bookmark       string            `json:"bookmark"`
+	Warning        string            `json:"warning,omitempty"`
+	ExecutionStats map[string]any    `json:"execution_stats,omitempty"`
+}
+
+// ExplainResult describes how CouchDB would execute a Mango query, as returned by Explain.
+type ExplainResult struct {
+	DBName   string         `json:"dbname"`
+	Index    IndexInfo      `json:"index"` // Index chosen to answer the query
+	Selector map[string]any `json:"selector"`
+	Opts     map[string]any `json:"opts"`
+	Limit    int            `json:"limit"`
+	Skip     int            `json:"skip"`
+	Fields   any            `json:"fields"`             // "all_fields" or the list of requested fields
+	MRArgs   ExplainMRArgs  `json:"mrargs"`             // Arguments of the underlying view query
+	Covering *bool          `json:"covering,omitempty"` // Whether the index covers the query; reported by CouchDB 3.3+
+}
+
+// ExplainMRArgs are the view query arguments CouchDB derives from a Mango query.
+type ExplainMRArgs struct {
+	StartKey    any    `json:"start_key"`
+	EndKey      any    `json:"end_key"`
+	Direction   string `json:"direction"`
+	IncludeDocs bool   `json:"include_docs"`
+	Reduce      bool   `json:"reduce"`
+	Stable      bool   `json:"stable"`
+	Update      any    `json:"update"`
+	ViewType    string `json:"view_type"`
+	Partition   any    `json:"partition"`
+}
+
+// Find runs a Mango query against the database and unmarshals the response into resultVar.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - query: The Mango query to run.
+//   - resultVar: A pointer to a struct (or map[string]interface{}) where the response will be unmarshalled.
+//     The matching documents are found under the "docs" JSON field; FindResponse can be used as a generic result.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - An error, if any, encountered while running the query or unmarshalling its result.
+//
+// Example:
+//
+//	var result struct {
+//	    Docs []Person `json:"docs"`
+//	}
+//	err := db.Find(ctx, couchdb.FindQuery{
+//	    Selector: map[string]any{"age": map[string]any{"$gt": 21}},
+//	}, &result)
+func (db *Database) Find(ctx context.Context, query FindQuery, resultVar any, opts ...RequestOption) error {
+	return db.find(ctx, fmt.Sprintf("%s/_find", db.dbName), query, resultVar, opts...)
+}
+
+// find runs a Mango query against the _find endpoint at endpoint and unmarshals the response into resultVar.
+func (db *Database) find(ctx context.Context, endpoint string, query FindQuery, resultVar any, opts ...RequestOption) error {
+	if !isValidParam(resultVar) {
+		return fmt.Errorf("resultVar parameter must be a pointer to a struct")
+	}
+
+	respCode, respBody, err := db.httpClient.Post(ctx, endpoint, query, opts...)
+	if err != nil {
+		return fmt.Errorf("error running find query: %w", err)
+	}
+
+	if respCode != 200 {
+		return responseError("error running find query", respCode, respBody)
+	}
+
+	err = json.Unmarshal(respBody, resultVar)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling into resultVar: %w", err)
+	}
+
+	return nil
+}
+
+// Explain reports how CouchDB would execute a Mango query without running it, which makes it possible
+// to verify, e.g. in tests, that a query is answered by the intended index.
+//
+// Example:
+//
+//	explain, err := db.Explain(ctx, query)
+//	if err != nil {
+//	    log.Fatalf("Error explaining query: %v", err)
+//	}
+//	if explain.Index.Name != "type-created-at" {
+//	    log.Fatalf("Query uses unexpected index %s", explain.Index.Name)
+//	}
+func (db *Database) Explain(ctx context.Context, query FindQuery, opts ...RequestOption) (*ExplainResult, error) {
+	respCode, respBody, err := db.httpClient.Post(ctx, fmt.Sprintf("%s/_explain", db.dbName), query, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error explaining query: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error explaining query", respCode, respBody)
+	}
+
+	var explainResult ExplainResult
+	err = json.Unmarshal(respBody, &explainResult)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling explain response: %w", err)
+	}
+
+	return &explainResult, nil
+}
@@ -0,0 +1,243 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCreateIndex(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statusCode       int
+		body             string
+		expectedResponse *CreateIndexResponse
+		expectedError    error
+	}{
+		{
+			name:             "created",
+			statusCode:       200,
+			body:             `{"result":"created","id":"_design/by-type","name":"type-created-at"}`,
+			expectedResponse: &CreateIndexResponse{Result: "created", ID: "_design/by-type", Name: "type-created-at"},
+		},
+		{
+			name:             "already exists",
+			statusCode:       200,
+			body:             `{"result":"exists","id":"_design/by-type","name":"type-created-at"}`,
+			expectedResponse: &CreateIndexResponse{Result: "exists", ID: "_design/by-type", Name: "type-created-at"},
+		},
+		{name: "invalid index", statusCode: 400, body: `{"error":"invalid_index","reason":"Index fields must be strings or objects."}`, expectedError: ErrBadRequest},
+		{name: "not an admin", statusCode: 403, body: `{"error":"forbidden","reason":"You are not a db or server admin."}`, expectedError: ErrForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/test/_index" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if r.Header.Get("X-Request-Id") != "req-1" {
+					t.Errorf("Expected the per-request header to be sent")
+				}
+
+				var index map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&index); err != nil {
+					t.Errorf("Unexpected request body: %v", err)
+				}
+				expected := map[string]any{
+					"index": map[string]any{"fields": []any{"type", map[string]any{"created_at": "desc"}}},
+					"ddoc":  "by-type",
+					"name":  "type-created-at",
+				}
+				if !reflect.DeepEqual(index, expected) {
+					t.Errorf("Expected index %v, got %v", expected, index)
+				}
+
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			resp, err := db.CreateIndex(context.Background(), Index{
+				Index: IndexDefinition{Fields: []any{"type", map[string]string{"created_at": "desc"}}},
+				DDoc:  "by-type",
+				Name:  "type-created-at",
+			}, WithHeader("X-Request-Id", "req-1"))
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if !reflect.DeepEqual(resp, tc.expectedResponse) {
+				t.Errorf("Expected response %+v, got %+v", tc.expectedResponse, resp)
+			}
+		})
+	}
+}
+
+func TestListIndexes(t *testing.T) {
+	testCases := []struct {
+		name            string
+		statusCode      int
+		body            string
+		expectedIndexes []IndexInfo
+		expectedError   error
+	}{
+		{
+			name:       "indexes",
+			statusCode: 200,
+			body: `{"total_rows":2,"indexes":[` +
+				`{"ddoc":null,"name":"_all_docs","type":"special","def":{"fields":[{"_id":"asc"}]}},` +
+				`{"ddoc":"_design/by-type","name":"type-created-at","type":"json","partitioned":false,"def":{"fields":[{"type":"asc"}],"partial_filter_selector":{"archived":false}}}]}`,
+			expectedIndexes: []IndexInfo{
+				{Name: "_all_docs", Type: "special", Def: IndexDefinition{Fields: []any{map[string]any{"_id": "asc"}}}},
+				{
+					DDoc: "_design/by-type",
+					Name: "type-created-at",
+					Type: "json",
+					Def: IndexDefinition{
+						Fields:                []any{map[string]any{"type": "asc"}},
+						PartialFilterSelector: map[string]any{"archived": false},
+					},
+				},
+			},
+		},
+		{name: "missing database", statusCode: 404, body: `{"error":"not_found","reason":"Database does not exist."}`, expectedError: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/test/_index" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			indexes, err := db.ListIndexes(context.Background())
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if !reflect.DeepEqual(indexes, tc.expectedIndexes) {
+				t.Errorf("Expected indexes %+v, got %+v", tc.expectedIndexes, indexes)
+			}
+		})
+	}
+}
+
+func TestDeleteIndex(t *testing.T) {
+	testCases := []struct {
+		name          string
+		ddoc          string
+		index         string
+		expectedPath  string
+		statusCode    int
+		body          string
+		expectedError error
+	}{
+		{name: "deleted", ddoc: "by-type", index: "type-created-at", expectedPath: "/test/_index/by-type/json/type-created-at", statusCode: 200, body: `{"ok":true}`},
+		{name: "prefixed design document", ddoc: "_design/by-type", index: "type-created-at", expectedPath: "/test/_index/by-type/json/type-created-at", statusCode: 200, body: `{"ok":true}`},
+		{name: "escaped names", ddoc: "by/type", index: "type #1", expectedPath: "/test/_index/by%2Ftype/json/type%20%231", statusCode: 200, body: `{"ok":true}`},
+		{name: "missing index", ddoc: "by-type", index: "missing", expectedPath: "/test/_index/by-type/json/missing", statusCode: 404, body: `{"error":"not_found","reason":"Index not found"}`, expectedError: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete || r.URL.EscapedPath() != tc.expectedPath {
+					t.Errorf("Expected DELETE %s, got %s %s", tc.expectedPath, r.Method, r.URL.EscapedPath())
+				}
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			if err := db.DeleteIndex(context.Background(), tc.ddoc, tc.index); !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected error %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	const explainBody = `{
+		"dbname": "test",
+		"index": {"ddoc": "_design/by-type", "name": "type-created-at", "type": "json", "partitioned": false, "def": {"fields": [{"type": "asc"}, {"created_at": "asc"}]}},
+		"partitioned": false,
+		"selector": {"type": {"$eq": "order"}},
+		"opts": {"use_index": [], "bookmark": "nil", "limit": 10, "skip": 0, "sort": {}, "fields": "all_fields", "r": [49], "conflicts": false},
+		"limit": 10,
+		"skip": 0,
+		"fields": "all_fields",
+		"mrargs": {"start_key": ["order"], "end_key": ["order", "<MAX>"], "direction": "fwd", "include_docs": true, "reduce": false, "stable": false, "update": true, "view_type": "map", "partition": null},
+		"covering": false
+	}`
+
+	testCases := []struct {
+		name          string
+		statusCode    int
+		body          string
+		expectedError error
+	}{
+		{name: "explain", statusCode: 200, body: explainBody},
+		{name: "invalid selector", statusCode: 400, body: `{"error":"invalid_operator","reason":"Invalid operator: $bad"}`, expectedError: ErrBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/test/_explain" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if r.Header.Get("X-Request-Id") != "req-1" {
+					t.Errorf("Expected the per-request header to be sent")
+				}
+				var query map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&query); err != nil || query["limit"] != float64(10) {
+					t.Errorf("Unexpected query %v, %v", query, err)
+				}
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			explain, err := db.Explain(context.Background(), FindQuery{Selector: map[string]any{"type": "order"}, Limit: 10}, WithHeader("X-Request-Id", "req-1"))
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if tc.expectedError != nil {
+				return
+			}
+
+			if explain.DBName != "test" || explain.Index.Name != "type-created-at" || explain.Index.DDoc != "_design/by-type" || explain.Limit != 10 {
+				t.Errorf("Unexpected explain result %+v", explain)
+			}
+			if explain.Fields != "all_fields" || explain.Opts["limit"] != float64(10) {
+				t.Errorf("Unexpected fields %v or options %v", explain.Fields, explain.Opts)
+			}
+			expectedArgs := ExplainMRArgs{
+				StartKey:    []any{"order"},
+				EndKey:      []any{"order", "<MAX>"},
+				Direction:   "fwd",
+				IncludeDocs: true,
+				Update:      true,
+				ViewType:    "map",
+			}
+			if !reflect.DeepEqual(explain.MRArgs, expectedArgs) {
+				t.Errorf("Expected view arguments %+v, got %+v", expectedArgs, explain.MRArgs)
+			}
+			if explain.Covering == nil || *explain.Covering {
+				t.Errorf("Expected the query to be reported as not covered, got %v", explain.Covering)
+			}
+		})
+	}
+}
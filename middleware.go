@@ -0,0 +1,51 @@
+package couchdb
+
+import "net/http"
+
+// Doer sends an HTTP request and returns its response, like *http.Client.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts a function to a Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer that sends the requests of a client, to observe or modify every attempt of every request,
+// e.g. to record metrics or add headers. A middleware must either call next or return a response or an error itself.
+type Middleware func(next Doer) Doer
+
+// WithMiddleware adds middlewares to the chain that sends the requests of the client. The first middleware
+// is the outermost one, so it sees the request first and the response last. Middlewares run after the request
+// is authenticated, once per attempt; retries, including those after renewing expired credentials, go through
+// the chain again.
+//
+// Example:
+//
+//	timing := func(next couchdb.Doer) couchdb.Doer {
+//	    return couchdb.DoerFunc(func(req *http.Request) (*http.Response, error) {
+//	        start := time.Now()
+//	        resp, err := next.Do(req)
+//	        requestDuration.WithLabelValues(req.Method).Observe(time.Since(start).Seconds())
+//	        return resp, err
+//	    })
+//	}
+//	cs, err := couchdb.New(url, couchdb.WithMiddleware(timing))
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *CustomHTTPClient) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// httpDo sends req through the middleware chain of the client.
+func (c *CustomHTTPClient) httpDo(req *http.Request) (*http.Response, error) {
+	var doer Doer = c.client
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		doer = c.middlewares[i](doer)
+	}
+	return doer.Do(req)
+}
@@ -0,0 +1,63 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Trace")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var calls []string
+	named := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				req.Header.Add("X-Trace", name)
+				resp, err := next.Do(req)
+				calls = append(calls, name+" after")
+				return resp, err
+			})
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		middlewares    []Middleware
+		expectedCalls  []string
+		expectedHeader string
+	}{
+		{name: "no middlewares", expectedCalls: nil, expectedHeader: ""},
+		{
+			name:           "chain order",
+			middlewares:    []Middleware{named("outer"), named("inner")},
+			expectedCalls:  []string{"outer before", "inner before", "inner after", "outer after"},
+			expectedHeader: "outer",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls = nil
+			client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second, WithMiddleware(tc.middlewares...))
+			if _, _, err := client.Get(context.Background(), "test"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(calls, tc.expectedCalls) {
+				t.Errorf("Expected calls %v, got %v", tc.expectedCalls, calls)
+			}
+			if header != tc.expectedHeader {
+				t.Errorf("Expected X-Trace %q, got %q", tc.expectedHeader, header)
+			}
+		})
+	}
+}
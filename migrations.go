@@ -0,0 +1,179 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// migrationsDocID is the ID of the local document where a Migrator records the applied migrations.
+// Local documents are not replicated, so every database keeps track of its own design documents.
+const migrationsDocID = "_local/go-couch-migrations"
+
+// DesignDocMigration is a versioned definition of a design document, registered in a Migrator.
+type DesignDocMigration struct {
+	Version   int            // Position of the migration; must be positive and unique within a Migrator
+	Name      string         // Name of the design document to write, without the "_design/" prefix
+	DesignDoc DesignDocument // Content of the design document as of this version
+}
+
+// MigratorOptions configure a Migrator.
+type MigratorOptions struct {
+	// BuildIndexes makes Migrate query the views of every migrated design document once all migrations are applied,
+	// so their indexes are built before the first real query needs them.
+	BuildIndexes bool
+}
+
+// Migrator applies versioned design document definitions to a database, in order and only once.
+//
+// The highest applied version is recorded in a local document of the database, so running the same
+// migrations against several environments brings each one up to date with whatever it is missing.
+type Migrator struct {
+	db         *Database
+	opts       MigratorOptions
+	migrations []DesignDocMigration
+}
+
+// migrationsState is the content of the local document recording the applied migrations.
+type migrationsState struct {
+	ID      string             `json:"_id"`
+	Rev     string             `json:"_rev,omitempty"`
+	Version int                `json:"version"`
+	Applied []appliedMigration `json:"applied"`
+}
+
+// appliedMigration is the record of a single applied migration.
+type appliedMigration struct {
+	Version   int       `json:"version"`
+	DesignDoc string    `json:"design_doc"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// NewMigrator creates a Migrator for the database.
+//
+// Example:
+//
+//	migrator := couchdb.NewMigrator(db, couchdb.MigratorOptions{BuildIndexes: true})
+//	migrator.Register(
+//	    couchdb.DesignDocMigration{Version: 1, Name: "people", DesignDoc: peopleV1},
+//	    couchdb.DesignDocMigration{Version: 2, Name: "people", DesignDoc: peopleV2},
+//	)
+//	applied, err := migrator.Migrate(ctx)
+//	if err != nil {
+//	    log.Fatalf("Error migrating design documents: %v", err)
+//	}
+func NewMigrator(db *Database, opts MigratorOptions) *Migrator {
+	return &Migrator{db: db, opts: opts}
+}
+
+// Register adds migrations to the Migrator. They can be registered in any order.
+func (m *Migrator) Register(migrations ...DesignDocMigration) {
+	m.migrations = append(m.migrations, migrations...)
+}
+
+// Migrate applies, in version order, every registered migration newer than the last one applied to the database,
+// recording progress after each of them.
+//
+// Design documents are written with SyncDesignDoc, so a migration whose content is already on the server
+// doesn't invalidate its indexes. If two processes migrate the same database concurrently, one of them fails
+// with a conflict when recording its progress, which is safe to retry.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP requests.
+//
+// Returns:
+//   - []int: The versions applied by this call, in order; empty if the database was already up to date.
+//   - error: An error, if any, encountered while applying the migrations. Versions applied before the error
+//     are recorded and returned.
+func (m *Migrator) Migrate(ctx context.Context) ([]int, error) {
+	migrations, err := m.sortedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	state := migrationsState{ID: migrationsDocID}
+	err = m.db.getDoc(ctx, migrationsDocID, GetDocOptions{}, &state)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("error getting migrations state: %w", err)
+	}
+
+	var applied []int
+	var migrated []string
+	for _, migration := range migrations {
+		if migration.Version <= state.Version {
+			continue
+		}
+
+		if _, err := m.db.SyncDesignDoc(ctx, migration.Name, migration.DesignDoc); err != nil {
+			return applied, fmt.Errorf("error applying migration %d: %w", migration.Version, err)
+		}
+
+		state.Version = migration.Version
+		state.Applied = append(state.Applied, appliedMigration{
+			Version:   migration.Version,
+			DesignDoc: migration.Name,
+			AppliedAt: time.Now().UTC(),
+		})
+		if err := m.saveState(ctx, &state); err != nil {
+			return applied, fmt.Errorf("error recording migration %d: %w", migration.Version, err)
+		}
+
+		applied = append(applied, migration.Version)
+		if !slices.Contains(migrated, migration.Name) {
+			migrated = append(migrated, migration.Name)
+		}
+	}
+
+	if m.opts.BuildIndexes {
+		for _, name := range migrated {
+			if err := m.db.WarmViews(ctx, name); err != nil {
+				return applied, fmt.Errorf("error building indexes of %s: %w", name, err)
+			}
+		}
+	}
+
+	return applied, nil
+}
+
+// sortedMigrations returns the registered migrations sorted by version, checking that they are valid.
+func (m *Migrator) sortedMigrations() ([]DesignDocMigration, error) {
+	migrations := make([]DesignDocMigration, len(m.migrations))
+	copy(migrations, m.migrations)
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	for i, migration := range migrations {
+		if migration.Version <= 0 {
+			return nil, fmt.Errorf("invalid migration version %d: versions must be positive", migration.Version)
+		}
+		if migration.Name == "" {
+			return nil, fmt.Errorf("invalid migration %d: missing design document name", migration.Version)
+		}
+		if i > 0 && migrations[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("duplicate migration version %d", migration.Version)
+		}
+	}
+	return migrations, nil
+}
+
+// saveState writes the migrations state, updating its revision.
+func (m *Migrator) saveState(ctx context.Context, state *migrationsState) error {
+	respCode, respBody, err := m.db.httpClient.Put(ctx, docPath(m.db.dbName, migrationsDocID), state)
+	if err != nil {
+		return err
+	}
+
+	if respCode != 200 && respCode != 201 {
+		return responseError("error saving migrations state", respCode, respBody)
+	}
+
+	var saveResponse CreateDocResponseType
+	if err := json.Unmarshal(respBody, &saveResponse); err != nil {
+		return fmt.Errorf("error unmarshalling migrations state response: %w", err)
+	}
+	state.Rev = saveResponse.Rev
+	return nil
+}
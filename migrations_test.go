@@ -0,0 +1,130 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDocServer is a minimal in-memory document store, enough to exercise read-modify-write flows.
+// Writes must carry the current revision of existing documents, as in CouchDB.
+type fakeDocServer struct {
+	mu   sync.Mutex
+	docs map[string]map[string]any
+	puts []string
+}
+
+func (s *fakeDocServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Documents are keyed by their ID, regardless of the database they are requested from.
+	id := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)[1]
+	current, exists := s.docs[id]
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			return
+		}
+		w.Header().Set("ETag", fmt.Sprintf("%q", current["_rev"]))
+		_ = json.NewEncoder(w).Encode(current)
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		var doc map[string]any
+		_ = json.Unmarshal(body, &doc)
+		if exists && doc["_rev"] != current["_rev"] {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":"conflict","reason":"Document update conflict."}`))
+			return
+		}
+		rev := fmt.Sprintf("%d-x", len(s.puts)+1)
+		doc["_rev"] = rev
+		s.docs[id] = doc
+		s.puts = append(s.puts, id)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"ok":true,"id":%q,"rev":%q}`, id, rev)
+	case http.MethodDelete:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			return
+		}
+		if r.URL.Query().Get("rev") != current["_rev"] {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":"conflict","reason":"Document update conflict."}`))
+			return
+		}
+		delete(s.docs, id)
+		_, _ = fmt.Fprintf(w, `{"ok":true,"id":%q,"rev":"%d-deleted"}`, id, len(s.puts)+1)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestMigratorMigrate(t *testing.T) {
+	fake := &fakeDocServer{docs: map[string]map[string]any{
+		migrationsDocID: {"_id": migrationsDocID, "_rev": "0-1", "version": 1},
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	migrator := NewMigrator(db, MigratorOptions{})
+	migrator.Register(
+		DesignDocMigration{Version: 3, Name: "b", DesignDoc: DesignDocument{Language: "javascript"}},
+		DesignDocMigration{Version: 1, Name: "a", DesignDoc: DesignDocument{Language: "javascript"}},
+		DesignDocMigration{Version: 2, Name: "a", DesignDoc: DesignDocument{Language: "javascript", ValidateDocUpdate: "function () {}"}},
+	)
+
+	applied, err := migrator.Migrate(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(applied, []int{2, 3}) {
+		t.Errorf("Expected versions [2 3] to be applied, got %v", applied)
+	}
+	expectedPuts := []string{"_design/a", migrationsDocID, "_design/b", migrationsDocID}
+	if !reflect.DeepEqual(fake.puts, expectedPuts) {
+		t.Errorf("Expected writes %v, got %v", expectedPuts, fake.puts)
+	}
+	if version := fake.docs[migrationsDocID]["version"]; version != float64(3) {
+		t.Errorf("Expected recorded version 3, got %v", version)
+	}
+
+	applied, err = migrator.Migrate(context.Background())
+	if err != nil || len(applied) != 0 {
+		t.Errorf("Expected nothing to apply on a second run, got %v, %v", applied, err)
+	}
+}
+
+func TestMigratorInvalidMigrations(t *testing.T) {
+	testCases := []struct {
+		name       string
+		migrations []DesignDocMigration
+	}{
+		{name: "non-positive version", migrations: []DesignDocMigration{{Version: 0, Name: "a"}}},
+		{name: "missing name", migrations: []DesignDocMigration{{Version: 1}}},
+		{name: "duplicate version", migrations: []DesignDocMigration{{Version: 1, Name: "a"}, {Version: 1, Name: "b"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			migrator := NewMigrator(&Database{}, MigratorOptions{})
+			migrator.Register(tc.migrations...)
+			if _, err := migrator.Migrate(context.Background()); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
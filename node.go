@@ -0,0 +1,242 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// localNode is the name that designates the node handling the request in the /_node endpoints.
+const localNode = "_local"
+
+// Node gives access to the endpoints of a single node of the cluster, such as its statistics.
+// It requires server admin privileges.
+type Node struct {
+	httpClient *CustomHTTPClient
+	name       string
+}
+
+// Node returns the handle of the node with the given name, as listed by Membership, e.g. "couchdb@10.0.0.1".
+// An empty name designates the node handling each request, which is the only one on single-node installs.
+//
+// Example:
+//
+//	stats, err := cs.Node("").Stats(ctx, "couchdb", "httpd", "requests")
+//	if err != nil {
+//	    log.Fatalf("Error getting node stats: %v", err)
+//	}
+func (c *CouchService) Node(name string) *Node {
+	if name == "" {
+		name = localNode
+	}
+	return &Node{httpClient: c.httpClient, name: name}
+}
+
+// endpoint returns the endpoint of the node made of the given path segments.
+func (n *Node) endpoint(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+	return strings.Join(append([]string{"_node", url.PathEscape(n.name)}, escaped...), "/")
+}
+
+// Stats returns the statistics of the node, or the subset of them under path, e.g. "couchdb", "request_time".
+// The statistics are nested objects whose leaves hold a "value", which is a number for counters and gauges
+// or an object of percentiles for histograms, along with its "type" and "desc".
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - path: The path of the statistics to return; empty to return all of them.
+//
+// Returns:
+//   - map[string]any: The statistics, as decoded from JSON.
+//   - error: An error, if any, encountered while getting the statistics. ErrNotFound is returned if path doesn't exist.
+func (n *Node) Stats(ctx context.Context, path ...string) (map[string]any, error) {
+	respCode, respBody, err := n.httpClient.Get(ctx, n.endpoint(append([]string{"_stats"}, path...)...))
+	if err != nil {
+		return nil, fmt.Errorf("error getting node stats: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting node stats", respCode, respBody)
+	}
+
+	var stats map[string]any
+	err = json.Unmarshal(respBody, &stats)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling node stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// SystemStats are the statistics of the Erlang VM of a node, as returned by System.
+type SystemStats struct {
+	Uptime                  int64            `json:"uptime"`                    // Seconds since the node started
+	Memory                  map[string]int64 `json:"memory"`                    // Bytes allocated, by kind, e.g. "processes" or "binary"
+	RunQueue                int              `json:"run_queue"`                 // Processes ready to run, waiting for a scheduler
+	ETSTableCount           int              `json:"ets_table_count"`           // Number of in-memory tables
+	ContextSwitches         int64            `json:"context_switches"`          // Total number of context switches
+	Reductions              int64            `json:"reductions"`                // Total number of reductions, a measure of the work done
+	GarbageCollectionCount  int64            `json:"garbage_collection_count"`  // Total number of garbage collections
+	WordsReclaimed          int64            `json:"words_reclaimed"`           // Total number of words reclaimed by garbage collection
+	IOInput                 int64            `json:"io_input"`                  // Total bytes received through ports
+	IOOutput                int64            `json:"io_output"`                 // Total bytes sent through ports
+	OSProcCount             int              `json:"os_proc_count"`             // Number of JavaScript query server processes
+	StaleProcCount          int              `json:"stale_proc_count"`          // Number of query server processes waiting to be stopped
+	ProcessCount            int              `json:"process_count"`             // Number of Erlang processes
+	ProcessLimit            int              `json:"process_limit"`             // Maximum number of Erlang processes
+	InternalReplicationJobs int              `json:"internal_replication_jobs"` // Number of pending replications between the shard copies of the cluster
+	MessageQueues           map[string]any   `json:"message_queues"`            // Length of the message queue of the main processes; a growing queue reveals a bottleneck
+	Distribution            map[string]any   `json:"distribution"`              // Statistics of the connections to the other nodes, by node name
+}
+
+// System returns the statistics of the Erlang VM of the node, such as its memory usage and message queues.
+//
+// Returns:
+//   - *SystemStats: The statistics of the node.
+//   - error: An error, if any, encountered while getting the statistics.
+func (n *Node) System(ctx context.Context) (*SystemStats, error) {
+	respCode, respBody, err := n.httpClient.Get(ctx, n.endpoint("_system"))
+	if err != nil {
+		return nil, fmt.Errorf("error getting node system stats: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting node system stats", respCode, respBody)
+	}
+
+	var stats SystemStats
+	err = json.Unmarshal(respBody, &stats)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling node system stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// Config returns the whole configuration of the node, by section and key.
+// Values are always strings, as in the ini files CouchDB reads them from.
+func (n *Node) Config(ctx context.Context) (map[string]map[string]string, error) {
+	var config map[string]map[string]string
+	if err := n.getConfig(ctx, &config, "_config"); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ConfigSection returns the keys and values of a section of the configuration of the node, e.g. "cors".
+func (n *Node) ConfigSection(ctx context.Context, section string) (map[string]string, error) {
+	var values map[string]string
+	if err := n.getConfig(ctx, &values, "_config", section); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// GetConfig returns a value of the configuration of the node.
+//
+// Returns:
+//   - string: The value; the passwords of the admins section are returned hashed.
+//   - error: ErrNotFound if the key is not set, or any other error encountered.
+func (n *Node) GetConfig(ctx context.Context, section, key string) (string, error) {
+	var value string
+	if err := n.getConfig(ctx, &value, "_config", section, key); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// getConfig gets the configuration at the given path of the node and unmarshals it into result.
+// Configuration values are bare strings, such as the hashed passwords of the admins or the secret of cookie sessions,
+// which can't be redacted by field, so the bodies of the configuration endpoints are never traced.
+func (n *Node) getConfig(ctx context.Context, result any, path ...string) error {
+	respCode, respBody, err := n.httpClient.Get(ctx, n.endpoint(path...), withoutBodyTracing())
+	if err != nil {
+		return fmt.Errorf("error getting node config: %w", err)
+	}
+
+	if respCode != 200 {
+		return responseError("error getting node config", respCode, respBody)
+	}
+
+	err = json.Unmarshal(respBody, result)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling node config: %w", err)
+	}
+
+	return nil
+}
+
+// SetConfig sets a value of the configuration of the node, which takes effect right away and is persisted
+// to its local.ini file. In a cluster, the configuration must be set on every node.
+//
+// Setting a key of the admins section creates a server admin, whose password CouchDB hashes before storing it.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - section: The section of the configuration, e.g. "chttpd_auth".
+//   - key: The key within the section, e.g. "timeout".
+//   - value: The new value.
+//
+// Returns:
+//   - string: The previous value; empty if the key wasn't set.
+//   - error: An error, if any, encountered while setting the value.
+//
+// Example:
+//
+//	node := cs.Node("")
+//	if _, err := node.SetConfig(ctx, "chttpd", "enable_cors", "true"); err != nil {
+//	    log.Fatalf("Error enabling CORS: %v", err)
+//	}
+//	if _, err := node.SetConfig(ctx, "cors", "origins", "https://app.example.com"); err != nil {
+//	    log.Fatalf("Error setting CORS origins: %v", err)
+//	}
+//	if _, err := node.SetConfig(ctx, "admins", "ops", "s3cret"); err != nil {
+//	    log.Fatalf("Error creating admin: %v", err)
+//	}
+func (n *Node) SetConfig(ctx context.Context, section, key, value string) (string, error) {
+	respCode, respBody, err := n.httpClient.Put(ctx, n.endpoint("_config", section, key), value, withoutBodyTracing())
+	if err != nil {
+		return "", fmt.Errorf("error setting node config: %w", err)
+	}
+
+	if respCode != 200 {
+		return "", responseError("error setting node config", respCode, respBody)
+	}
+
+	var previous string
+	err = json.Unmarshal(respBody, &previous)
+	if err != nil {
+		return "", fmt.Errorf("error unmarshalling previous node config: %w", err)
+	}
+
+	return previous, nil
+}
+
+// DeleteConfig removes a value from the configuration of the node, e.g. a server admin from the admins section.
+//
+// Returns:
+//   - string: The removed value.
+//   - error: ErrNotFound if the key is not set, or any other error encountered.
+func (n *Node) DeleteConfig(ctx context.Context, section, key string) (string, error) {
+	respCode, respBody, err := n.httpClient.Delete(ctx, n.endpoint("_config", section, key), withoutBodyTracing())
+	if err != nil {
+		return "", fmt.Errorf("error deleting node config: %w", err)
+	}
+
+	if respCode != 200 {
+		return "", responseError("error deleting node config", respCode, respBody)
+	}
+
+	var previous string
+	err = json.Unmarshal(respBody, &previous)
+	if err != nil {
+		return "", fmt.Errorf("error unmarshalling previous node config: %w", err)
+	}
+
+	return previous, nil
+}
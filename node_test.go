@@ -0,0 +1,153 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNodeStats(t *testing.T) {
+	var receivedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.EscapedPath()
+		switch r.URL.Path {
+		case "/_node/_local/_stats":
+			w.Write([]byte(`{"couchdb":{"open_databases":{"value":12,"type":"counter","desc":"number of open databases"}}}`))
+		case "/_node/couchdb@10.0.0.1/_stats/couchdb/request_time":
+			w.Write([]byte(`{"value":{"min":0.5,"max":120.2,"arithmetic_mean":3.4},"type":"histogram","desc":"length of a request inside CouchDB without MochiWeb"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not_found","reason":"Unknown stat"}`))
+		}
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	testCases := []struct {
+		name         string
+		node         string
+		path         []string
+		expectedPath string
+		expectedType string
+		err          error
+	}{
+		{name: "all stats of the local node", expectedPath: "/_node/_local/_stats"},
+		{
+			name:         "single stat of a named node",
+			node:         "couchdb@10.0.0.1",
+			path:         []string{"couchdb", "request_time"},
+			expectedPath: "/_node/couchdb@10.0.0.1/_stats/couchdb/request_time",
+			expectedType: "histogram",
+		},
+		{name: "unknown stat", path: []string{"missing"}, expectedPath: "/_node/_local/_stats/missing", err: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stats, err := cs.Node(tc.node).Stats(context.Background(), tc.path...)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if receivedPath != tc.expectedPath {
+				t.Errorf("Expected path %s, got %s", tc.expectedPath, receivedPath)
+			}
+			if tc.expectedType != "" && stats["type"] != tc.expectedType {
+				t.Errorf("Expected stat of type %s, got %v", tc.expectedType, stats)
+			}
+		})
+	}
+}
+
+func TestNodeSystem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_node/_local/_system" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"uptime":3600,"memory":{"processes":31457280,"binary":1048576},"run_queue":1,
+			"process_count":1024,"process_limit":262144,"os_proc_count":2,"message_queues":{"couch_file":{"count":3}}}`))
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	stats, err := cs.Node("").System(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.Uptime != 3600 || stats.Memory["processes"] != 31457280 || stats.ProcessCount != 1024 || stats.OSProcCount != 2 || stats.MessageQueues["couch_file"] == nil {
+		t.Errorf("Unexpected system stats %+v", stats)
+	}
+}
+
+func TestNodeConfig(t *testing.T) {
+	config := map[string]map[string]string{"chttpd_auth": {"timeout": "600"}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/_node/_local/_config"), "/")[1:]
+		switch {
+		case len(segments) == 0:
+			json.NewEncoder(w).Encode(config)
+		case len(segments) == 1:
+			json.NewEncoder(w).Encode(config[segments[0]])
+		case r.Method == http.MethodPut:
+			var value string
+			json.NewDecoder(r.Body).Decode(&value)
+			previous := config[segments[0]][segments[1]]
+			if config[segments[0]] == nil {
+				config[segments[0]] = map[string]string{}
+			}
+			config[segments[0]][segments[1]] = value
+			json.NewEncoder(w).Encode(previous)
+		default:
+			value, ok := config[segments[0]][segments[1]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":"not_found","reason":"unknown_config_value"}`))
+				return
+			}
+			if r.Method == http.MethodDelete {
+				delete(config[segments[0]], segments[1])
+			}
+			json.NewEncoder(w).Encode(value)
+		}
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+	node := cs.Node("")
+	ctx := context.Background()
+
+	previous, err := node.SetConfig(ctx, "chttpd_auth", "timeout", "3600")
+	if err != nil || previous != "600" {
+		t.Fatalf("Expected previous value 600, got %q, %v", previous, err)
+	}
+	if previous, err := node.SetConfig(ctx, "admins", "ops", "s3cret"); err != nil || previous != "" {
+		t.Fatalf("Expected no previous value, got %q, %v", previous, err)
+	}
+
+	value, err := node.GetConfig(ctx, "chttpd_auth", "timeout")
+	if err != nil || value != "3600" {
+		t.Errorf("Expected value 3600, got %q, %v", value, err)
+	}
+	section, err := node.ConfigSection(ctx, "admins")
+	if err != nil || section["ops"] != "s3cret" {
+		t.Errorf("Expected the ops admin, got %v, %v", section, err)
+	}
+
+	if previous, err := node.DeleteConfig(ctx, "admins", "ops"); err != nil || previous != "s3cret" {
+		t.Errorf("Expected deleted value s3cret, got %q, %v", previous, err)
+	}
+	if _, err := node.GetConfig(ctx, "admins", "ops"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after deletion, got %v", err)
+	}
+
+	all, err := node.Config(ctx)
+	if err != nil || all["chttpd_auth"]["timeout"] != "3600" || len(all["admins"]) != 0 {
+		t.Errorf("Unexpected config %v, %v", all, err)
+	}
+}
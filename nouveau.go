@@ -0,0 +1,116 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// NouveauIndex defines a Nouveau index in the NouveauIndexes field of a design document.
+// Nouveau is the Lucene-based search of CouchDB 3.4 and later, which must be enabled and backed by a Nouveau server.
+type NouveauIndex struct {
+	// Index is the JavaScript function called for each document, which indexes its fields with
+	// index(type, name, value, options), the type being one of "text", "string", "double" and "stored".
+	Index string `json:"index"`
+	// DefaultAnalyzer is the name of the Lucene analyzer of the text fields, e.g. "standard" (the default) or "english".
+	DefaultAnalyzer string `json:"default_analyzer,omitempty"`
+	// FieldAnalyzers overrides DefaultAnalyzer for specific fields, by field name.
+	FieldAnalyzers map[string]string `json:"field_analyzers,omitempty"`
+}
+
+// NouveauRange is a named range of values of a double field, to count the matches of with NouveauQuery.Ranges.
+type NouveauRange struct {
+	Label        string  `json:"label"`
+	Min          float64 `json:"min"`
+	Max          float64 `json:"max"`
+	MinInclusive *bool   `json:"min_inclusive,omitempty"` // Whether Min is included; the server default is true
+	MaxInclusive *bool   `json:"max_inclusive,omitempty"` // Whether Max is included; the server default is true
+}
+
+// NouveauQuery are the parameters of a Nouveau query, as described [here](https://docs.couchdb.org/en/stable/api/ddoc/nouveau.html).
+type NouveauQuery struct {
+	Query       string                    `json:"q"`                      // Lucene query, e.g. "title:couch* AND year:[2010 TO 2020]"
+	Sort        []string                  `json:"sort,omitempty"`         // Fields to sort by, prefixed with "-" for descending order, e.g. "-year<double>"
+	Limit       int                       `json:"limit,omitempty"`        // Maximum number of hits to return; the server default is 25
+	Bookmark    string                    `json:"bookmark,omitempty"`     // Bookmark returned by a previous query, for pagination
+	IncludeDocs bool                      `json:"include_docs,omitempty"` // Include the matching document in each hit
+	Counts      []string                  `json:"counts,omitempty"`       // String fields to count the distinct values of among the matches
+	Ranges      map[string][]NouveauRange `json:"ranges,omitempty"`       // Double fields to count the matches of by range
+	TopN        int                       `json:"top_n,omitempty"`        // Maximum number of distinct values reported for each field in Counts
+	Locale      string                    `json:"locale,omitempty"`       // Locale used to parse numbers in the query
+	Update      *bool                     `json:"update,omitempty"`       // Whether to update the index before answering; the server default is true
+}
+
+// NouveauSortValue is a typed sort value of a NouveauHit.
+type NouveauSortValue struct {
+	Type  string `json:"@type"` // Type of the value, e.g. "float" for relevance scores, "double" or "string"
+	Value any    `json:"value"`
+}
+
+// NouveauHit is a document matching a Nouveau query.
+type NouveauHit struct {
+	ID     string             `json:"id"`
+	Order  []NouveauSortValue `json:"order"`         // Sort values of the hit, e.g. its relevance score
+	Fields map[string]any     `json:"fields"`        // Stored fields of the hit
+	Doc    json.RawMessage    `json:"doc,omitempty"` // Matching document; only set with NouveauQuery.IncludeDocs
+}
+
+// NouveauResponse is the response of a Nouveau query. It can be used as the resultVar of NouveauSearch.
+type NouveauResponse struct {
+	TotalHits         int                       `json:"total_hits"`          // Number of documents matching the query, regardless of Limit
+	TotalHitsRelation string                    `json:"total_hits_relation"` // "EQUAL_TO", or "GREATER_THAN_OR_EQUAL_TO" if TotalHits is a lower bound
+	Bookmark          string                    `json:"bookmark"`            // Bookmark to pass in the next query to get the following hits
+	Hits              []NouveauHit              `json:"hits"`                // Matching documents
+	Counts            map[string]map[string]int `json:"counts,omitempty"`    // Number of matches by value of each field in NouveauQuery.Counts
+	Ranges            map[string]map[string]int `json:"ranges,omitempty"`    // Number of matches by range label of each field in NouveauQuery.Ranges
+}
+
+// NouveauSearch runs a Lucene query against a Nouveau index defined in the NouveauIndexes field of a design document.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - design: The name of the design document, with or without the "_design/" prefix.
+//   - index: The name of the Nouveau index.
+//   - query: The parameters of the query.
+//   - resultVar: A pointer to a struct (such as NouveauResponse) or map where the response will be unmarshalled.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - error: An error, if any, encountered while running the query.
+//     ErrBadRequest is returned if the query is not valid Lucene syntax.
+//
+// Example:
+//
+//	var result couchdb.NouveauResponse
+//	err := db.NouveauSearch(ctx, "books", "by_text", couchdb.NouveauQuery{
+//	    Query: "title:couch* AND year:[2010 TO *]",
+//	    Sort:  []string{"-year<double>"},
+//	}, &result)
+//	if err != nil {
+//	    log.Fatalf("Error searching books: %v", err)
+//	}
+//	for _, hit := range result.Hits {
+//	    fmt.Println(hit.ID, hit.Fields["title"])
+//	}
+func (db *Database) NouveauSearch(ctx context.Context, design, index string, query NouveauQuery, resultVar any, opts ...RequestOption) error {
+	if !isValidParam(resultVar) {
+		return fmt.Errorf("resultVar parameter must be a pointer to a struct")
+	}
+
+	endpoint := designPath(db.dbName, design, "_nouveau", index)
+	respCode, respBody, err := db.httpClient.Post(ctx, endpoint, query, opts...)
+	if err != nil {
+		return fmt.Errorf("error running nouveau query: %w", err)
+	}
+
+	if respCode != 200 {
+		return responseError("error running nouveau query", respCode, respBody)
+	}
+
+	err = json.Unmarshal(respBody, resultVar)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling into resultVar: %w", err)
+	}
+
+	return nil
+}
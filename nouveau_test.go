@@ -0,0 +1,92 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNouveauSearch(t *testing.T) {
+	var received map[string]any
+	var receivedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		received = nil
+		json.NewDecoder(r.Body).Decode(&received)
+		if received["q"] == "title:[" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad_request","reason":"Cannot parse 'title:['"}`))
+			return
+		}
+		w.Write([]byte(`{"total_hits":1,"total_hits_relation":"EQUAL_TO","bookmark":"W10=","hits":[
+			{"id":"book1","order":[{"@type":"float","value":1.5},{"@type":"string","value":"book1"}],
+			 "fields":{"title":"CouchDB"},"doc":{"_id":"book1","_rev":"1-a"}}
+		],"ranges":{"price":{"cheap":1}}}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name         string
+		query        NouveauQuery
+		expectedBody map[string]any
+		err          error
+	}{
+		{
+			name:         "query only",
+			query:        NouveauQuery{Query: "title:couch*"},
+			expectedBody: map[string]any{"q": "title:couch*"},
+		},
+		{
+			name: "ranges and docs",
+			query: NouveauQuery{
+				Query:       "title:couch*",
+				IncludeDocs: true,
+				Ranges:      map[string][]NouveauRange{"price": {{Label: "cheap", Min: 0, Max: 10}}},
+			},
+			expectedBody: map[string]any{
+				"q":            "title:couch*",
+				"include_docs": true,
+				"ranges":       map[string]any{"price": []any{map[string]any{"label": "cheap", "min": float64(0), "max": float64(10)}}},
+			},
+		},
+		{
+			name:         "invalid query",
+			query:        NouveauQuery{Query: "title:["},
+			expectedBody: map[string]any{"q": "title:["},
+			err:          ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var result NouveauResponse
+			err := db.NouveauSearch(context.Background(), "books", "by_text", tc.query, &result)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if receivedPath != "/test/_design/books/_nouveau/by_text" {
+				t.Errorf("Unexpected path %s", receivedPath)
+			}
+			if !reflect.DeepEqual(received, tc.expectedBody) {
+				t.Errorf("Expected body %v, got %v", tc.expectedBody, received)
+			}
+			if tc.err != nil {
+				return
+			}
+			if result.TotalHits != 1 || len(result.Hits) != 1 || result.Ranges["price"]["cheap"] != 1 {
+				t.Fatalf("Unexpected result %+v", result)
+			}
+			hit := result.Hits[0]
+			if hit.Order[0] != (NouveauSortValue{Type: "float", Value: 1.5}) || hit.Fields["title"] != "CouchDB" || len(hit.Doc) == 0 {
+				t.Errorf("Unexpected hit %+v", hit)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Partition is a handle to a single partition of a partitioned database.
+//
+// Queries made through a Partition only read the shard holding the partition, instead of every shard
+// of the database, which makes them much cheaper than their global equivalents.
+type Partition struct {
+	db   *Database
+	name string
+}
+
+// Partition returns a handle for the queries scoped to the given partition of a partitioned database.
+// Documents belong to the partition named by the part of their ID before the colon, e.g. "sensor-1" for "sensor-1:reading-42".
+//
+// Example:
+//
+//	var readings couchdb.AllDocsResponse
+//	err := db.Partition("sensor-1").AllDocs(ctx, couchdb.AllDocsOptions{IncludeDocs: true}, &readings)
+//	if err != nil {
+//	    log.Fatalf("Error listing partition documents: %v", err)
+//	}
+func (db *Database) Partition(name string) *Partition {
+	return &Partition{db: db, name: name}
+}
+
+// partitionPath returns the path of a partition of the database.
+func partitionPath(dbName, partition string) string {
+	return fmt.Sprintf("%s/_partition/%s", dbName, escapePathSegment(partition))
+}
+
+// endpoint returns the path of a partition endpoint.
+func (p *Partition) endpoint(path string) string {
+	return fmt.Sprintf("%s/%s", partitionPath(p.db.dbName, p.name), path)
+}
+
+// AllDocs lists the documents of the partition, like Database.AllDocs.
+func (p *Partition) AllDocs(ctx context.Context, opts AllDocsOptions, resultVar any, reqOpts ...RequestOption) error {
+	return p.db.queryAllDocs(ctx, p.endpoint("_all_docs"), opts, nil, resultVar, reqOpts...)
+}
+
+// View queries a partitioned view, returning only the rows emitted by documents of the partition, like Database.View.
+func (p *Partition) View(ctx context.Context, design, view string, params ViewParams, resultVar any, opts ...RequestOption) error {
+	return p.db.queryView(ctx, designPath(partitionPath(p.db.dbName, p.name), design, "_view", view), params, resultVar, opts...)
+}
+
+// Find runs a Mango query on the documents of the partition, like Database.Find.
+// The query can only use partitioned indexes.
+func (p *Partition) Find(ctx context.Context, query FindQuery, resultVar any, opts ...RequestOption) error {
+	return p.db.find(ctx, p.endpoint("_find"), query, resultVar, opts...)
+}
+
+// PartitionInfo is the information about a partition of a database.
+type PartitionInfo struct {
+	DBName      string  `json:"db_name"`
+	Partition   string  `json:"partition"`
+	DocCount    int64   `json:"doc_count"`     // Number of documents, excluding deleted ones
+	DocDelCount int64   `json:"doc_del_count"` // Number of deleted documents
+	Sizes       DBSizes `json:"sizes"`         // Active and external sizes of the partition; File is not reported
+}
+
+// PartitionInfo returns the information about a partition of the database, such as its document count and sizes.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - partition: The name of the partition.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - *PartitionInfo: The partition information.
+//   - error: An error, if any, encountered while getting the partition information.
+func (db *Database) PartitionInfo(ctx context.Context, partition string, opts ...RequestOption) (*PartitionInfo, error) {
+	respCode, respBody, err := db.httpClient.Get(ctx, partitionPath(db.dbName, partition), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting partition info: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting partition info", respCode, respBody)
+	}
+
+	var info PartitionInfo
+	err = json.Unmarshal(respBody, &info)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling partition info: %w", err)
+	}
+
+	return &info, nil
+}
@@ -0,0 +1,114 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPartitionEndpoints(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(`{"total_rows":0,"offset":0,"rows":[],"docs":[]}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+	partition := db.Partition("sensor-1")
+
+	testCases := []struct {
+		name     string
+		query    func(ctx context.Context) error
+		expected string
+	}{
+		{
+			name: "all docs",
+			query: func(ctx context.Context) error {
+				return partition.AllDocs(ctx, AllDocsOptions{}, &AllDocsResponse{})
+			},
+			expected: "/test/_partition/sensor-1/_all_docs",
+		},
+		{
+			name: "view",
+			query: func(ctx context.Context) error {
+				return partition.View(ctx, "ddoc", "by_time", ViewParams{}, &AllDocsResponse{})
+			},
+			expected: "/test/_partition/sensor-1/_design/ddoc/_view/by_time",
+		},
+		{
+			name: "find",
+			query: func(ctx context.Context) error {
+				return partition.Find(ctx, FindQuery{Selector: map[string]any{}}, &FindResponse{})
+			},
+			expected: "/test/_partition/sensor-1/_find",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			paths = nil
+			if err := tc.query(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(paths) != 1 || paths[0] != tc.expected {
+				t.Errorf("Expected a request to %s, got %v", tc.expected, paths)
+			}
+		})
+	}
+}
+
+func TestPartitionInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/test/_partition/sensor-1":
+			_, _ = w.Write([]byte(`{"db_name":"test","partition":"sensor-1","doc_count":42,"doc_del_count":3,"sizes":{"active":2048,"external":1024}}`))
+		case "/test/_partition/sensor%2F2":
+			_, _ = w.Write([]byte(`{"db_name":"test","partition":"sensor/2","doc_count":0,"doc_del_count":0,"sizes":{"active":0,"external":0}}`))
+		case "/plain/_partition/sensor-1":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"bad_request","reason":"database is not partitioned"}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.EscapedPath())
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name          string
+		db            string
+		partition     string
+		expected      *PartitionInfo
+		expectedError error
+	}{
+		{
+			name:      "partition",
+			db:        "test",
+			partition: "sensor-1",
+			expected:  &PartitionInfo{DBName: "test", Partition: "sensor-1", DocCount: 42, DocDelCount: 3, Sizes: DBSizes{Active: 2048, External: 1024}},
+		},
+		{
+			name:      "escaped partition name",
+			db:        "test",
+			partition: "sensor/2",
+			expected:  &PartitionInfo{DBName: "test", Partition: "sensor/2"},
+		},
+		{name: "database not partitioned", db: "plain", partition: "sensor-1", expectedError: ErrBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: tc.db}
+			info, err := db.PartitionInfo(context.Background(), tc.partition)
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if tc.expected != nil && *info != *tc.expected {
+				t.Errorf("Expected %+v, got %+v", *tc.expected, *info)
+			}
+		})
+	}
+}
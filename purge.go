@@ -0,0 +1,102 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PurgeResponse is the response of the _purge endpoint.
+type PurgeResponse struct {
+	PurgeSeq Seq                 `json:"purge_seq"` // Purge sequence after the purge; empty on clustered databases
+	Purged   map[string][]string `json:"purged"`    // Revisions actually purged, by document ID
+}
+
+// Purge permanently removes document revisions from the database, as if they had never existed, which is needed
+// for GDPR-style erasure. Unlike deleted documents, purged revisions leave no tombstone, so the removal is not
+// replicated: the same revisions must be purged on every replica.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - revs: The revisions to purge, by document ID. Purging every leaf revision of a document removes it completely.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - *PurgeResponse: The revisions that were purged.
+//   - error: An error, if any, encountered while purging the revisions.
+//
+// Example:
+//
+//	resp, err := db.Purge(ctx, map[string][]string{"user:42": {"3-c1a0", "2-b7e1"}})
+//	if err != nil {
+//	    log.Fatalf("Error purging user: %v", err)
+//	}
+func (db *Database) Purge(ctx context.Context, revs map[string][]string, opts ...RequestOption) (*PurgeResponse, error) {
+	respCode, respBody, err := db.httpClient.Post(ctx, fmt.Sprintf("%s/_purge", db.dbName), revs, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error purging docs: %w", err)
+	}
+
+	if respCode != 201 && respCode != 202 {
+		return nil, responseError("error purging docs", respCode, respBody)
+	}
+
+	var purgeResponse PurgeResponse
+	err = json.Unmarshal(respBody, &purgeResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling purge response: %w", err)
+	}
+
+	return &purgeResponse, nil
+}
+
+// GetPurgedInfosLimit returns how many purge requests the database keeps track of, so that indexes and
+// internal replication can catch up with them. It defaults to 1000.
+func (db *Database) GetPurgedInfosLimit(ctx context.Context, opts ...RequestOption) (int, error) {
+	return db.getLimit(ctx, "_purged_infos_limit", opts...)
+}
+
+// SetPurgedInfosLimit sets how many purge requests the database keeps track of. It must be raised if more purges
+// than the limit can happen while an index is not being updated, or the index will have to be rebuilt.
+func (db *Database) SetPurgedInfosLimit(ctx context.Context, limit int, opts ...RequestOption) error {
+	return db.setLimit(ctx, "_purged_infos_limit", limit, opts...)
+}
+
+// getLimit reads a database setting exposed as a bare number at the given endpoint of the database.
+func (db *Database) getLimit(ctx context.Context, name string, opts ...RequestOption) (int, error) {
+	respCode, respBody, err := db.httpClient.Get(ctx, fmt.Sprintf("%s/%s", db.dbName, name), opts...)
+	if err != nil {
+		return 0, fmt.Errorf("error getting %s: %w", name, err)
+	}
+
+	if respCode != 200 {
+		return 0, responseError(fmt.Sprintf("error getting %s", name), respCode, respBody)
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(string(respBody)))
+	if err != nil {
+		return 0, fmt.Errorf("error parsing %s: %w", name, err)
+	}
+
+	return limit, nil
+}
+
+// setLimit writes a database setting exposed as a bare number at the given endpoint of the database.
+func (db *Database) setLimit(ctx context.Context, name string, limit int, opts ...RequestOption) error {
+	if limit <= 0 {
+		return fmt.Errorf("invalid %s %d: must be positive", name, limit)
+	}
+
+	respCode, respBody, err := db.httpClient.Put(ctx, fmt.Sprintf("%s/%s", db.dbName, name), limit, opts...)
+	if err != nil {
+		return fmt.Errorf("error setting %s: %w", name, err)
+	}
+
+	if respCode != 200 {
+		return responseError(fmt.Sprintf("error setting %s", name), respCode, respBody)
+	}
+
+	return nil
+}
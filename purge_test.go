@@ -0,0 +1,87 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPurge(t *testing.T) {
+	var received map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/test/_purge" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"purge_seq":null,"purged":{"user:42":["3-c1a0"]}}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+	revs := map[string][]string{"user:42": {"3-c1a0", "2-b7e1"}}
+	resp, err := db.Purge(context.Background(), revs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(received, revs) {
+		t.Errorf("Expected %v to be sent, got %v", revs, received)
+	}
+	if !reflect.DeepEqual(resp.Purged, map[string][]string{"user:42": {"3-c1a0"}}) || resp.PurgeSeq != "" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestPurgedInfosLimit(t *testing.T) {
+	limit := "1000"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test/_purged_infos_limit" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			limit = string(body)
+			w.Write([]byte(`{"ok":true}`))
+			return
+		}
+		w.Write([]byte(limit + "\n"))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name          string
+		set           int
+		expected      int
+		expectedError bool
+	}{
+		{name: "default limit", expected: 1000},
+		{name: "raised limit", set: 5000, expected: 5000},
+		{name: "invalid limit", set: -1, expected: 5000, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.set != 0 {
+				err := db.SetPurgedInfosLimit(context.Background(), tc.set)
+				if (err != nil) != tc.expectedError {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+			got, err := db.GetPurgedInfosLimit(context.Background())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("Expected %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
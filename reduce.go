@@ -0,0 +1,96 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// BuiltinReduce is one of the reduce functions implemented natively by CouchDB,
+// which are much faster than equivalent JavaScript reduce functions.
+type BuiltinReduce string
+
+const (
+	ReduceSum                 BuiltinReduce = "_sum"                   // Sums the numeric values (or arrays of numbers, element-wise)
+	ReduceCount               BuiltinReduce = "_count"                 // Counts the rows
+	ReduceStats               BuiltinReduce = "_stats"                 // Computes a StatsReduceValue over the numeric values
+	ReduceApproxCountDistinct BuiltinReduce = "_approx_count_distinct" // Estimates the number of distinct keys
+)
+
+// View returns a view definition using mapFn as map function and the builtin reduce function.
+//
+// Example:
+//
+//	err := db.CreateDesignDoc(ctx, "sales", map[string]couchdb.ViewDefinition{
+//	    "total_by_month": couchdb.ReduceSum.View("function (doc) { emit(doc.month, doc.amount); }"),
+//	})
+func (r BuiltinReduce) View(mapFn string) ViewDefinition {
+	return ViewDefinition{Map: mapFn, Reduce: string(r)}
+}
+
+// StatsReduceValue is the value of a row reduced with ReduceStats.
+// If the view emits arrays of numbers, the value is an array holding one StatsReduceValue per element.
+type StatsReduceValue struct {
+	Sum    float64 `json:"sum"`
+	Count  int64   `json:"count"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	SumSqr float64 `json:"sumsqr"`
+}
+
+// ReduceRow is a single row of a reduced view query. Key is null unless the results are grouped.
+type ReduceRow struct {
+	Key   json.RawMessage `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ViewReduce queries a view with its reduce function and returns the reduction rows.
+//
+// Unlike View, it does not require rows to have an ID, since reduced rows don't belong to a single document.
+// params.Reduce is always enabled; set params.Group or params.GroupLevel to get one row per key (or key prefix)
+// instead of a single row for the whole key range.
+//
+// Parameters:
+//   - ctx: The context for the HTTP request.
+//   - design: The design document name.
+//   - view: The name of the view within the design document, which must define a reduce function.
+//   - params: The parameters for the view query.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - []ReduceRow: The reduction rows, whose keys and values can be unmarshalled into the types emitted by the view.
+//   - error: An error if the view query fails.
+//
+// Example:
+//
+//	rows, err := db.ViewReduce(ctx, "sales", "total_by_month", couchdb.ViewParams{GroupLevel: 2})
+//	if err != nil {
+//	    log.Fatalf("Error reducing view: %v", err)
+//	}
+//	for _, row := range rows {
+//	    var total float64
+//	    _ = json.Unmarshal(row.Value, &total)
+//	}
+func (db *Database) ViewReduce(ctx context.Context, design, view string, params ViewParams, opts ...RequestOption) ([]ReduceRow, error) {
+	reduce := true
+	params.Reduce = &reduce
+
+	code, responseBytes, err := db.httpClient.Post(ctx, designPath(db.dbName, design, "_view", view), params, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting reduced view: %w", err)
+	}
+
+	if code != 200 {
+		return nil, responseError("error getting reduced view", code, responseBytes)
+	}
+
+	var result struct {
+		Rows []ReduceRow `json:"rows"`
+	}
+	err = json.Unmarshal(responseBytes, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling reduced view response: %w", err)
+	}
+
+	return result.Rows, nil
+}
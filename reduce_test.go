@@ -0,0 +1,59 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestViewReduce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params ViewParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Errorf("Unexpected body: %v", err)
+		}
+		if params.Reduce == nil || !*params.Reduce || params.GroupLevel != 1 {
+			t.Errorf("Unexpected params: %+v", params)
+		}
+		_, _ = w.Write([]byte(`{"rows":[{"key":["2024"],"value":3},{"key":["2025"],"value":5}]}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	rows, err := db.ViewReduce(context.Background(), "ddoc", "view", ViewParams{GroupLevel: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rows) != 2 || string(rows[1].Key) != `["2025"]` || string(rows[1].Value) != "5" {
+		t.Errorf("Unexpected rows: %s", rows)
+	}
+}
+
+func TestBuiltinReduceView(t *testing.T) {
+	testCases := []struct {
+		name     string
+		reduce   BuiltinReduce
+		expected string
+	}{
+		{name: "sum", reduce: ReduceSum, expected: `{"map":"m","reduce":"_sum"}`},
+		{name: "count", reduce: ReduceCount, expected: `{"map":"m","reduce":"_count"}`},
+		{name: "stats", reduce: ReduceStats, expected: `{"map":"m","reduce":"_stats"}`},
+		{name: "approx count distinct", reduce: ReduceApproxCountDistinct, expected: `{"map":"m","reduce":"_approx_count_distinct"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(tc.reduce.View("m"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(got) != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ReplicationEndpoint is the source or target database of a replication.
+type ReplicationEndpoint struct {
+	URL     string            `json:"url"`               // Full URL of the database, e.g. "https://replica.example.com/orders"
+	Headers map[string]string `json:"headers,omitempty"` // Headers sent with every request to the database
+	Auth    *ReplicationAuth  `json:"auth,omitempty"`    // Credentials of the database; nil if none or if they are in the URL
+}
+
+// UnmarshalJSON decodes an endpoint given either as an object or, as is common in replication documents,
+// as the bare URL of the database.
+func (e *ReplicationEndpoint) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*e = ReplicationEndpoint{}
+		return json.Unmarshal(data, &e.URL)
+	}
+	type endpoint ReplicationEndpoint
+	return json.Unmarshal(data, (*endpoint)(e))
+}
+
+// ReplicationAuth holds the credentials the replicator uses to access a database.
+type ReplicationAuth struct {
+	Basic *ReplicationBasicAuth `json:"basic,omitempty"`
+}
+
+// ReplicationBasicAuth holds basic authentication credentials.
+type ReplicationBasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// NewReplicationEndpoint returns the endpoint of the database at url, accessed with the given basic authentication
+// credentials. Empty credentials leave the endpoint unauthenticated.
+func NewReplicationEndpoint(url, username, password string) ReplicationEndpoint {
+	endpoint := ReplicationEndpoint{URL: url}
+	if username != "" || password != "" {
+		endpoint.Auth = &ReplicationAuth{Basic: &ReplicationBasicAuth{Username: username, Password: password}}
+	}
+	return endpoint
+}
+
+// ReplicationRequest are the parameters of a replication, as described [here](https://docs.couchdb.org/en/stable/api/server/common.html#replicate).
+type ReplicationRequest struct {
+	Source             ReplicationEndpoint `json:"source"`
+	Target             ReplicationEndpoint `json:"target"`
+	CreateTarget       bool                `json:"create_target,omitempty"`        // Create the target database if it doesn't exist
+	CreateTargetParams map[string]any      `json:"create_target_params,omitempty"` // Parameters of the created target, e.g. {"q": 8, "partitioned": true}
+	Continuous         bool                `json:"continuous,omitempty"`           // Keep replicating new changes until cancelled
+	Cancel             bool                `json:"cancel,omitempty"`               // Cancel the continuous replication with the same parameters
+	DocIDs             []string            `json:"doc_ids,omitempty"`              // Replicate only these documents
+	Filter             string              `json:"filter,omitempty"`               // Filter function of the source, as "ddoc/filter", applied to each document
+	QueryParams        map[string]string   `json:"query_params,omitempty"`         // Parameters passed to Filter as req.query
+	Selector           any                 `json:"selector,omitempty"`             // Mango selector the replicated documents must match; faster than Filter
+	SinceSeq           Seq                 `json:"since_seq,omitempty"`            // Source sequence to start replicating from, ignoring checkpoints
+	WinningRevsOnly    bool                `json:"winning_revs_only,omitempty"`    // Replicate only the winning revision of each document, not its conflicts
+	UseCheckpoints     *bool               `json:"use_checkpoints,omitempty"`      // Whether to record checkpoints to resume from; the server default is true
+}
+
+// ReplicationHistory is a session of a replication, as recorded in its checkpoints.
+type ReplicationHistory struct {
+	SessionID        string `json:"session_id"`
+	StartTime        string `json:"start_time"`
+	EndTime          string `json:"end_time"`
+	StartLastSeq     Seq    `json:"start_last_seq"`
+	EndLastSeq       Seq    `json:"end_last_seq"`
+	RecordedSeq      Seq    `json:"recorded_seq"`
+	MissingChecked   int    `json:"missing_checked"`    // Number of revisions checked for existence on the target
+	MissingFound     int    `json:"missing_found"`      // Number of revisions missing from the target
+	DocsRead         int    `json:"docs_read"`          // Number of documents read from the source
+	DocsWritten      int    `json:"docs_written"`       // Number of documents written to the target
+	DocWriteFailures int    `json:"doc_write_failures"` // Number of documents the target rejected, e.g. by its validation functions
+}
+
+// ReplicationResponse is the response to a replication request.
+// One-shot replications report their outcome in History; continuous ones only report LocalID.
+type ReplicationResponse struct {
+	Ok                   bool                 `json:"ok"`
+	SessionID            string               `json:"session_id,omitempty"`
+	SourceLastSeq        Seq                  `json:"source_last_seq,omitempty"` // Last source sequence replicated
+	ReplicationIDVersion int                  `json:"replication_id_version,omitempty"`
+	History              []ReplicationHistory `json:"history,omitempty"`    // Sessions of the replication, the most recent first
+	NoChanges            bool                 `json:"no_changes,omitempty"` // Whether there was nothing to replicate
+	LocalID              string               `json:"_local_id,omitempty"`  // ID of a continuous replication, to cancel it with
+}
+
+// Replicate replicates a database through the _replicate endpoint. One-shot replications complete before
+// Replicate returns, so they may need a longer timeout, set with WithRequestTimeout; continuous ones run in
+// the background until cancelled or the server restarts. Use the _replicator database for replications
+// that must survive restarts.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - replication: The parameters of the replication.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - *ReplicationResponse: The outcome of the replication.
+//   - error: An error, if any, encountered while replicating, e.g. ErrNotFound if a database doesn't exist.
+//
+// Example:
+//
+//	resp, err := cs.Replicate(ctx, couchdb.ReplicationRequest{
+//	    Source:       couchdb.NewReplicationEndpoint("http://localhost:5984/orders", "admin", "s3cret"),
+//	    Target:       couchdb.NewReplicationEndpoint("https://backup.example.com/orders", "backup", "pa55"),
+//	    CreateTarget: true,
+//	}, couchdb.WithRequestTimeout(10*time.Minute))
+//	if err != nil {
+//	    log.Fatalf("Error replicating orders: %v", err)
+//	}
+//	fmt.Println(resp.History[0].DocsWritten)
+func (c *CouchService) Replicate(ctx context.Context, replication ReplicationRequest, opts ...RequestOption) (*ReplicationResponse, error) {
+	respCode, respBody, err := c.httpClient.Post(ctx, "_replicate", replication, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error replicating: %w", err)
+	}
+
+	if respCode != 200 && respCode != 202 {
+		return nil, responseError("error replicating", respCode, respBody)
+	}
+
+	var replicationResponse ReplicationResponse
+	err = json.Unmarshal(respBody, &replicationResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling replication response: %w", err)
+	}
+
+	return &replicationResponse, nil
+}
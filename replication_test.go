@@ -0,0 +1,112 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReplicate(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/_replicate" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		received = nil
+		json.NewDecoder(r.Body).Decode(&received)
+		switch {
+		case received["source"].(map[string]any)["url"] == "http://localhost:5984/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not_found","reason":"could not open http://localhost:5984/missing/"}`))
+		case received["continuous"] == true:
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"ok":true,"_local_id":"0a81b645497e6270611ec3419767a584+continuous"}`))
+		default:
+			w.Write([]byte(`{"ok":true,"session_id":"s1","source_last_seq":"5-g1AAAA","replication_id_version":4,
+				"history":[{"session_id":"s1","start_last_seq":0,"end_last_seq":"5-g1AAAA","docs_read":5,"docs_written":5,"doc_write_failures":0}]}`))
+		}
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	testCases := []struct {
+		name         string
+		replication  ReplicationRequest
+		expectedBody map[string]any
+		expected     ReplicationResponse
+		err          error
+	}{
+		{
+			name: "one-shot",
+			replication: ReplicationRequest{
+				Source:       NewReplicationEndpoint("http://localhost:5984/orders", "admin", "s3cret"),
+				Target:       NewReplicationEndpoint("http://backup:5984/orders", "", ""),
+				CreateTarget: true,
+				Selector:     map[string]any{"type": "order"},
+			},
+			expectedBody: map[string]any{
+				"source": map[string]any{
+					"url":  "http://localhost:5984/orders",
+					"auth": map[string]any{"basic": map[string]any{"username": "admin", "password": "s3cret"}},
+				},
+				"target":        map[string]any{"url": "http://backup:5984/orders"},
+				"create_target": true,
+				"selector":      map[string]any{"type": "order"},
+			},
+			expected: ReplicationResponse{
+				Ok:                   true,
+				SessionID:            "s1",
+				SourceLastSeq:        "5-g1AAAA",
+				ReplicationIDVersion: 4,
+				History:              []ReplicationHistory{{SessionID: "s1", StartLastSeq: "0", EndLastSeq: "5-g1AAAA", DocsRead: 5, DocsWritten: 5}},
+			},
+		},
+		{
+			name: "continuous",
+			replication: ReplicationRequest{
+				Source:     ReplicationEndpoint{URL: "http://localhost:5984/orders"},
+				Target:     ReplicationEndpoint{URL: "http://backup:5984/orders"},
+				Continuous: true,
+			},
+			expectedBody: map[string]any{
+				"source":     map[string]any{"url": "http://localhost:5984/orders"},
+				"target":     map[string]any{"url": "http://backup:5984/orders"},
+				"continuous": true,
+			},
+			expected: ReplicationResponse{Ok: true, LocalID: "0a81b645497e6270611ec3419767a584+continuous"},
+		},
+		{
+			name: "missing source",
+			replication: ReplicationRequest{
+				Source: ReplicationEndpoint{URL: "http://localhost:5984/missing"},
+				Target: ReplicationEndpoint{URL: "http://backup:5984/orders"},
+			},
+			expectedBody: map[string]any{
+				"source": map[string]any{"url": "http://localhost:5984/missing"},
+				"target": map[string]any{"url": "http://backup:5984/orders"},
+			},
+			err: ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := cs.Replicate(context.Background(), tc.replication)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(received, tc.expectedBody) {
+				t.Errorf("Expected body %v, got %v", tc.expectedBody, received)
+			}
+			if tc.err == nil && !reflect.DeepEqual(*resp, tc.expected) {
+				t.Errorf("Expected %+v, got %+v", tc.expected, *resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const replicatorDBName = "_replicator"
+
+// States of a replication, as reported in ReplicationDoc.State and by the scheduler.
+// Replication documents only record the terminal states, ReplicationCompleted and ReplicationFailed.
+const (
+	ReplicationInitializing = "initializing" // The replication is being added to the scheduler
+	ReplicationError        = "error"        // The replication can't be started, e.g. because its source doesn't exist; it is retried later
+	ReplicationPending      = "pending"      // The replication is waiting to be run by the scheduler
+	ReplicationRunning      = "running"      // The replication is running
+	ReplicationCrashing     = "crashing"     // The replication failed while running and is backing off before being retried
+	ReplicationCompleted    = "completed"    // The one-shot replication finished successfully
+	ReplicationFailed       = "failed"       // The replication document is invalid, so it is never run
+)
+
+// ReplicationStats are the statistics of a running or completed replication.
+type ReplicationStats struct {
+	ChangesPending        int `json:"changes_pending"`
+	CheckpointedSourceSeq Seq `json:"checkpointed_source_seq"`
+	DocWriteFailures      int `json:"doc_write_failures"` // Number of documents the target rejected, e.g. by its validation functions
+	DocsRead              int `json:"docs_read"`
+	DocsWritten           int `json:"docs_written"`
+	MissingRevisionsFound int `json:"missing_revisions_found"`
+	RevisionsChecked      int `json:"revisions_checked"`
+}
+
+// ReplicationDoc is a persistent replication, stored in the _replicator database.
+// Unlike those started with Replicate, persistent replications survive server restarts.
+//
+// The fields prefixed with State and Stats are set by the replicator and ignored when the document is written.
+type ReplicationDoc struct {
+	Document
+	ReplicationRequest
+
+	State         string            `json:"_replication_state,omitempty"`        // One of ReplicationCompleted and ReplicationFailed, once reached
+	StateTime     string            `json:"_replication_state_time,omitempty"`   // When State was reached, in RFC 3339 format
+	StateReason   string            `json:"_replication_state_reason,omitempty"` // Why the replication failed
+	ReplicationID string            `json:"_replication_id,omitempty"`           // ID of the replication, as reported by the scheduler
+	Stats         *ReplicationStats `json:"_replication_stats,omitempty"`        // Statistics of the completed replication
+}
+
+// Replicator manages the persistent replications of the server, stored in the _replicator database.
+// It requires server admin privileges.
+type Replicator struct {
+	db *Database
+}
+
+// Replicator returns the handle for managing the persistent replications of the server.
+//
+// Example:
+//
+//	_, err := cs.Replicator().CreateReplication(ctx, couchdb.ReplicationDoc{
+//	    Document: couchdb.Document{ID: "orders-backup"},
+//	    ReplicationRequest: couchdb.ReplicationRequest{
+//	        Source:     couchdb.NewReplicationEndpoint("http://localhost:5984/orders", "admin", "s3cret"),
+//	        Target:     couchdb.NewReplicationEndpoint("https://backup.example.com/orders", "backup", "pa55"),
+//	        Continuous: true,
+//	    },
+//	})
+func (c *CouchService) Replicator() *Replicator {
+	return &Replicator{db: &Database{httpClient: c.httpClient, dbName: replicatorDBName}}
+}
+
+// CreateReplication stores a replication document, which the replicator starts running shortly after.
+// The ID of the document is required, so creating the same replication twice fails with ErrConflict.
+//
+// Returns:
+//   - *UpdateDocResponse: The ID and revision of the replication document.
+//   - error: An error, if any, encountered while storing the replication document.
+func (r *Replicator) CreateReplication(ctx context.Context, doc ReplicationDoc) (*UpdateDocResponse, error) {
+	if doc.ID == "" {
+		return nil, ErrMissingID
+	}
+	doc.Rev = ""
+	return r.db.putDoc(ctx, doc.ID, doc)
+}
+
+// GetReplication returns a replication document, e.g. to poll whether a one-shot replication completed.
+//
+// Returns:
+//   - *ReplicationDoc: The replication document.
+//   - error: ErrNotFound if the replication doesn't exist, or any other error encountered.
+func (r *Replicator) GetReplication(ctx context.Context, id string) (*ReplicationDoc, error) {
+	var doc ReplicationDoc
+	if err := r.db.getDoc(ctx, id, GetDocOptions{}, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// ListReplications returns every replication document, skipping the design documents of the database.
+func (r *Replicator) ListReplications(ctx context.Context) ([]ReplicationDoc, error) {
+	var allDocs AllDocsResponse
+	if err := r.db.AllDocs(ctx, AllDocsOptions{IncludeDocs: true}, &allDocs); err != nil {
+		return nil, err
+	}
+
+	docs := make([]ReplicationDoc, 0, len(allDocs.Rows))
+	for _, row := range allDocs.Rows {
+		if strings.HasPrefix(row.ID, "_design/") {
+			continue
+		}
+		var doc ReplicationDoc
+		if err := json.Unmarshal(row.Doc, &doc); err != nil {
+			return nil, fmt.Errorf("error unmarshalling replication doc %s: %w", row.ID, err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// CancelReplication deletes a replication document, which stops the replication if it is running.
+//
+// Returns:
+//   - An error, if any, encountered while deleting the replication document.
+//     ErrNotFound is returned if the replication doesn't exist.
+func (r *Replicator) CancelReplication(ctx context.Context, id string) error {
+	return r.db.DeleteDoc(ctx, id)
+}
@@ -0,0 +1,111 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReplicator(t *testing.T) {
+	fake := &fakeDocServer{docs: map[string]map[string]any{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+	replicator := cs.Replicator()
+	ctx := context.Background()
+
+	doc := ReplicationDoc{
+		Document: Document{ID: "orders-backup"},
+		ReplicationRequest: ReplicationRequest{
+			Source:     NewReplicationEndpoint("http://localhost:5984/orders", "admin", "s3cret"),
+			Target:     ReplicationEndpoint{URL: "http://backup:5984/orders"},
+			Continuous: true,
+		},
+	}
+	if _, err := replicator.CreateReplication(ctx, ReplicationDoc{}); !errors.Is(err, ErrMissingID) {
+		t.Errorf("Expected ErrMissingID, got %v", err)
+	}
+	if _, err := replicator.CreateReplication(ctx, doc); err != nil {
+		t.Fatalf("Unexpected error creating replication: %v", err)
+	}
+	if _, err := replicator.CreateReplication(ctx, doc); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected ErrConflict creating the replication twice, got %v", err)
+	}
+
+	stored := fake.docs["orders-backup"]
+	if stored["continuous"] != true || stored["_replication_state"] != nil {
+		t.Errorf("Unexpected replication doc %v", stored)
+	}
+
+	// The replicator records the terminal states in the document.
+	stored["_replication_state"] = ReplicationFailed
+	stored["_replication_state_reason"] = "unauthorized"
+	got, err := replicator.GetReplication(ctx, "orders-backup")
+	if err != nil {
+		t.Fatalf("Unexpected error getting replication: %v", err)
+	}
+	if got.State != ReplicationFailed || got.StateReason != "unauthorized" || got.Source.Auth.Basic.Username != "admin" {
+		t.Errorf("Unexpected replication %+v", got)
+	}
+
+	// Replication documents written by other tools often give the endpoints as bare URLs.
+	fake.docs["plain-backup"] = map[string]any{
+		"_id":    "plain-backup",
+		"_rev":   "1-p",
+		"source": "http://localhost:5984/orders",
+		"target": "http://backup:5984/orders",
+	}
+	plain, err := replicator.GetReplication(ctx, "plain-backup")
+	if err != nil {
+		t.Fatalf("Unexpected error getting replication with URL endpoints: %v", err)
+	}
+	if plain.Source.URL != "http://localhost:5984/orders" || plain.Target.URL != "http://backup:5984/orders" || plain.Source.Auth != nil {
+		t.Errorf("Unexpected endpoints %+v and %+v", plain.Source, plain.Target)
+	}
+
+	if err := replicator.CancelReplication(ctx, "orders-backup"); err != nil {
+		t.Fatalf("Unexpected error cancelling replication: %v", err)
+	}
+	if _, err := replicator.GetReplication(ctx, "orders-backup"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after cancelling, got %v", err)
+	}
+}
+
+func TestListReplications(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_replicator/_all_docs" || r.URL.Query().Get("include_docs") != "true" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"total_rows":3,"offset":0,"rows":[
+			{"id":"_design/_replicator","key":"_design/_replicator","value":{"rev":"1-a"},"doc":{"_id":"_design/_replicator","_rev":"1-a"}},
+			{"id":"orders-backup","key":"orders-backup","value":{"rev":"1-b"},"doc":{
+				"_id":"orders-backup","_rev":"1-b","source":{"url":"http://localhost:5984/orders"},"target":{"url":"http://backup:5984/orders"},
+				"_replication_state":"completed","_replication_stats":{"docs_read":3,"docs_written":3,"doc_write_failures":1}}},
+			{"id":"users-backup","key":"users-backup","value":{"rev":"1-c"},"doc":{
+				"_id":"users-backup","_rev":"1-c","source":"http://localhost:5984/users","target":"http://backup:5984/users","continuous":true}}
+		]}`))
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	docs, err := cs.Replicator().ListReplications(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("Expected 2 replications, got %d", len(docs))
+	}
+	doc := docs[0]
+	if doc.ID != "orders-backup" || doc.State != ReplicationCompleted || doc.Stats == nil || doc.Stats.DocWriteFailures != 1 {
+		t.Errorf("Unexpected replication %+v", doc)
+	}
+	plain := docs[1]
+	if plain.ID != "users-backup" || plain.Source.URL != "http://localhost:5984/users" || plain.Target.URL != "http://backup:5984/users" || !plain.Continuous {
+		t.Errorf("Unexpected replication with URL endpoints %+v", plain)
+	}
+}
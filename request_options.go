@@ -0,0 +1,85 @@
+package couchdb
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RequestOption customizes a single request, without affecting the client or the other requests made through it.
+// Options are applied in order, after the parameters derived from the arguments of the method.
+type RequestOption func(*request)
+
+// WithHeader adds a header to the request, e.g. If-Match to make a write conditional on the current revision.
+// Headers set this way take precedence over those set by the client, such as Accept.
+//
+// Example:
+//
+//	_, err := db.UpdateDoc(ctx, "invoice:42", invoice, couchdb.WithHeader("If-Match", rev))
+func WithHeader(key, value string) RequestOption {
+	return func(r *request) {
+		if r.header == nil {
+			r.header = http.Header{}
+		}
+		r.header.Add(key, value)
+	}
+}
+
+// WithRequestTimeout overrides the timeout of each attempt of the request, e.g. to give a slow view query
+// more time than the client timeout allows.
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(r *request) {
+		r.timeout = timeout
+	}
+}
+
+// WithQueryParam adds a query parameter to the request, for parameters the method doesn't expose,
+// e.g. batch=ok to let the server acknowledge a write before committing it.
+func WithQueryParam(key, value string) RequestOption {
+	return func(r *request) {
+		if r.query == nil {
+			r.query = url.Values{}
+		}
+		r.query.Add(key, value)
+	}
+}
+
+// withoutBodyTracing keeps the bodies of the request and its response out of debug traces altogether,
+// for endpoints whose bodies are secrets on their own, such as bare JSON strings, which sanitizeBody can't redact.
+func withoutBodyTracing() RequestOption {
+	return func(r *request) {
+		r.noTrace = true
+	}
+}
+
+// ResponseInfo holds the metadata of a response, as captured by CaptureResponse.
+type ResponseInfo struct {
+	StatusCode int         // Status code of the response
+	Header     http.Header // All the headers of the response
+	ETag       string      // ETag of the response without its quotes; for documents, their current revision
+	RequestID  string      // X-Couch-Request-ID assigned by the server, which identifies the request in its logs
+}
+
+// CaptureResponse stores the metadata of the final response of the request in info, so that, e.g., errors can be
+// correlated with the server logs through their request ID. info is left untouched if no response is received.
+//
+// Example:
+//
+//	var info couchdb.ResponseInfo
+//	if err := db.GetDoc(ctx, id, &doc, couchdb.CaptureResponse(&info)); err != nil {
+//	    log.Printf("Error getting document (request %s): %v", info.RequestID, err)
+//	}
+func CaptureResponse(info *ResponseInfo) RequestOption {
+	return func(r *request) {
+		r.capture = info
+	}
+}
+
+// set fills info from resp.
+func (info *ResponseInfo) set(resp *response) {
+	info.StatusCode = resp.statusCode
+	info.Header = resp.header
+	info.ETag = strings.Trim(resp.header.Get("ETag"), `"`)
+	info.RequestID = resp.header.Get("X-Couch-Request-ID")
+}
@@ -0,0 +1,256 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestOptions(t *testing.T) {
+	// The handler of a timed-out request may still be running when the next test case starts.
+	var mu sync.Mutex
+	var received *http.Request
+	slowDone := make(chan struct{}, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = r.Clone(context.Background())
+		mu.Unlock()
+		if r.URL.Query().Get("slow") == "true" {
+			defer func() { slowDone <- struct{}{} }()
+			select {
+			case <-time.After(100 * time.Millisecond):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.Write([]byte(`{"_id":"doc1","_rev":"1-abc"}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, 50*time.Millisecond), dbName: "test"}
+
+	testCases := []struct {
+		name          string
+		opts          []RequestOption
+		expectedQuery string
+		expectedError bool
+		check         func(t *testing.T, r *http.Request)
+	}{
+		{
+			name: "no options",
+			check: func(t *testing.T, r *http.Request) {
+				if r.Header.Get("If-Match") != "" {
+					t.Errorf("Expected no If-Match header, got %q", r.Header.Get("If-Match"))
+				}
+			},
+		},
+		{
+			name: "headers",
+			opts: []RequestOption{WithHeader("If-Match", "1-abc"), WithHeader("Accept", "text/plain")},
+			check: func(t *testing.T, r *http.Request) {
+				if r.Header.Get("If-Match") != "1-abc" || r.Header.Get("Accept") != "text/plain" {
+					t.Errorf("Expected the extra headers, got %v", r.Header)
+				}
+			},
+		},
+		{
+			name:          "query parameters",
+			opts:          []RequestOption{WithQueryParam("conflicts", "true"), WithQueryParam("latest", "true")},
+			expectedQuery: "conflicts=true&latest=true",
+		},
+		{
+			name:          "client timeout",
+			opts:          []RequestOption{WithQueryParam("slow", "true")},
+			expectedQuery: "slow=true",
+			expectedError: true,
+		},
+		{
+			name:          "request timeout",
+			opts:          []RequestOption{WithQueryParam("slow", "true"), WithRequestTimeout(time.Second)},
+			expectedQuery: "slow=true",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var doc Document
+			err := db.GetDoc(context.Background(), "doc1", &doc, tc.opts...)
+			if tc.expectedError {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("Expected a timeout, got %v", err)
+				}
+				// The client gave up on the request, which unblocks its handler.
+				<-slowDone
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			mu.Lock()
+			r := received
+			mu.Unlock()
+			if r.URL.RawQuery != tc.expectedQuery {
+				t.Errorf("Expected query %q, got %q", tc.expectedQuery, r.URL.RawQuery)
+			}
+			if tc.check != nil {
+				tc.check(t, r)
+			}
+		})
+	}
+}
+
+func TestCaptureResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Couch-Request-ID", "f3a1c0ffee")
+		if r.URL.Path == "/test/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			return
+		}
+		w.Header().Set("ETag", `"1-abc"`)
+		w.Write([]byte(`{"_id":"doc1","_rev":"1-abc"}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name     string
+		id       string
+		expected ResponseInfo
+		err      error
+	}{
+		{name: "document found", id: "doc1", expected: ResponseInfo{StatusCode: 200, ETag: "1-abc", RequestID: "f3a1c0ffee"}},
+		{name: "document missing", id: "missing", expected: ResponseInfo{StatusCode: 404, RequestID: "f3a1c0ffee"}, err: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var info ResponseInfo
+			var doc Document
+			err := db.GetDoc(context.Background(), tc.id, &doc, CaptureResponse(&info))
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if info.StatusCode != tc.expected.StatusCode || info.ETag != tc.expected.ETag || info.RequestID != tc.expected.RequestID {
+				t.Errorf("Expected %+v, got %+v", tc.expected, info)
+			}
+			if info.Header.Get("X-Couch-Request-ID") == "" {
+				t.Errorf("Expected the response headers to be captured")
+			}
+		})
+	}
+}
+
+func TestRequestOptionsReachEveryRequest(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if r.Header.Get("X-Request-Id") != "req-1" {
+			t.Errorf("Expected the per-request header on %s %s", r.Method, r.URL.Path)
+		}
+
+		if r.URL.Query().Get("feed") == "continuous" {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusCreated)
+		}
+		w.Write([]byte(`{"_id":"_design/ddoc","_rev":"1-abc","views":{"by_time":{"map":"function (doc) {}"}},"rev":"2-def","results":[],"last_seq":"1","rows":[],"docs":[]}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+	opt := WithHeader("X-Request-Id", "req-1")
+
+	testCases := []struct {
+		name  string
+		query func(ctx context.Context) error
+	}{
+		{
+			name: "put attachment",
+			query: func(ctx context.Context) error {
+				_, err := db.PutAttachment(ctx, "doc1", "1-abc", "notes.txt", "text/plain", strings.NewReader("notes"), opt)
+				return err
+			},
+		},
+		{
+			name: "attachment info",
+			query: func(ctx context.Context) error {
+				_, err := db.AttachmentInfo(ctx, "doc1", "notes.txt", opt)
+				return err
+			},
+		},
+		{
+			name: "multipart doc",
+			query: func(ctx context.Context) error {
+				var doc Document
+				_, err := db.GetDocMultipart(ctx, "doc1", GetDocOptions{}, &doc, opt)
+				return err
+			},
+		},
+		{
+			name: "changes",
+			query: func(ctx context.Context) error {
+				_, err := db.WaitForChanges(ctx, ChangesOptions{Timeout: time.Millisecond}, opt)
+				return err
+			},
+		},
+		{
+			name: "continuous changes",
+			query: func(ctx context.Context) error {
+				feed, err := db.ContinuousChanges(ctx, ChangesOptions{}, opt)
+				if err != nil {
+					return err
+				}
+				feed.Close()
+				return feed.Err()
+			},
+		},
+		{
+			name: "warm views",
+			query: func(ctx context.Context) error {
+				return db.WarmViews(ctx, "ddoc", opt)
+			},
+		},
+		{
+			name: "partition view",
+			query: func(ctx context.Context) error {
+				return db.Partition("sensor-1").View(ctx, "ddoc", "by_time", ViewParams{}, &AllDocsResponse{}, opt)
+			},
+		},
+		{
+			name: "partition find",
+			query: func(ctx context.Context) error {
+				return db.Partition("sensor-1").Find(ctx, FindQuery{Selector: map[string]any{}}, &FindResponse{}, opt)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mu.Lock()
+			requests = nil
+			mu.Unlock()
+			if err := tc.query(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if len(requests) == 0 {
+				t.Errorf("Expected at least one request")
+			}
+		})
+	}
+}
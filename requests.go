@@ -2,115 +2,666 @@ package couchdb
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
+const (
+	// mediaTypeJSON is the media type used for request bodies and negotiated for responses unless a request asks otherwise.
+	mediaTypeJSON = "application/json"
+	// mediaTypeMultipartRelated is negotiated to receive a document along with its raw attachments.
+	mediaTypeMultipartRelated = "multipart/related"
+)
+
+// Defaults of the clients created by New.
+const (
+	defaultMaxRetries = 5
+	defaultRetryWait  = 2 * time.Second
+	defaultTimeout    = 30 * time.Second
+)
+
+// maxDrainSize is the most bytes read from an unconsumed response body before closing it. Bodies drained to the end
+// let the transport reuse their connection; longer ones are cheaper to abandon along with it.
+const maxDrainSize = 256 << 10
+
+// defaultMaintenanceRetryWait is the default wait before retrying a request rejected because of compaction or resharding.
+const defaultMaintenanceRetryWait = 15 * time.Second
+
+// maintenanceMarkers are fragments of the error names and reasons CouchDB reports while a database
+// is being compacted or resharded.
+var maintenanceMarkers = []string{"compaction", "reshard", "shard_split"}
+
 // CustomHTTPClient represents an HTTP client with configurable settings.
 // It allows making HTTP requests with options for timeout and retries.
 type CustomHTTPClient struct {
-	baseURL    string        // Base URL for the HTTP client
-	client     *http.Client  // HTTP client for making requests
-	maxRetries int           // Maximum number of retries for failed requests
-	retryWait  time.Duration // Duration to wait between retries
-	timeout    time.Duration // Timeout for each HTTP request
+	baseURL              string          // Base URL for the HTTP client
+	client               *http.Client    // HTTP client for making requests
+	maxRetries           int             // Maximum number of retries for failed requests
+	retryWait            time.Duration   // Duration to wait before the first retry
+	backoff              Backoff         // Growth of the wait between retries
+	retryPolicy          RetryPolicy     // Overrides the built-in retry decisions; nil uses them as-is
+	breaker              *circuitBreaker // Fails requests fast while the server is down; nil disables it
+	limiter              *rateLimiter    // Spaces out attempts to the configured rate; nil disables it
+	inFlight             chan struct{}   // Holds a slot per attempt in flight, up to its capacity; nil means no limit
+	middlewares          []Middleware    // Wrap the sending of every attempt, outermost first
+	timeout              time.Duration   // Timeout for each HTTP request
+	compressionThreshold int             // Minimum request body size, in bytes, to send gzip-compressed; 0 disables compression
+	maintenanceRetryWait time.Duration   // Duration to wait between retries of requests rejected because of compaction or resharding
+	upsertAttempts       int             // Maximum number of writes of a document by Upsert
+	logger               *slog.Logger    // Logger for request attempts; nil disables logging
+	traceBodies          bool            // Whether to include sanitized request and response bodies in the logs
+	auth                 Authenticator   // Attaches credentials to each request; nil if they are embedded in baseURL
+	gzipResponses        bool            // Whether to ask for gzip-compressed responses and decompress them
+	maxResponseSize      int64           // Maximum size of a response body, in bytes, once decompressed; 0 means no limit
+	username             string          // User name set by WithAuth, applied by New
+	password             string          // Password set by WithAuth, applied by New
+
+	transportOpts []func(*http.Transport) // Applied by the options that configure the transport, replayed by WithHTTPClient
+}
+
+// Option configures a CustomHTTPClient.
+// Options passed to GetInstance apply to every database handle obtained from the resulting service.
+type Option func(*CustomHTTPClient)
+
+// WithRetries sets the maximum number of attempts of a request that fails with a transport error or a 5xx response.
+// It defaults to 5; a value of 1 or less disables retries.
+func WithRetries(maxRetries int) Option {
+	return func(c *CustomHTTPClient) {
+		c.maxRetries = maxRetries
+	}
+}
+
+// WithRetryWait sets how long to wait before retrying a failed request for the first time. It defaults to 2 seconds;
+// later retries wait longer, as configured by WithBackoff.
+func WithRetryWait(wait time.Duration) Option {
+	return func(c *CustomHTTPClient) {
+		c.retryWait = wait
+	}
+}
+
+// WithTimeout sets the timeout of each attempt of a request. It defaults to 30 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *CustomHTTPClient) {
+		c.timeout = timeout
+	}
+}
+
+// WithAuth sets the credentials of the client. They are embedded in the base URL unless an option such as
+// WithBasicAuth or WithCookieAuth selects another scheme, in which case that scheme uses them.
+func WithAuth(username, password string) Option {
+	return func(c *CustomHTTPClient) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithCompressionThreshold enables gzip compression of request bodies that are at least minSize bytes long.
+// Smaller bodies are sent as-is, since compressing them costs more than it saves.
+// A non-positive minSize disables request compression, which is the default.
+func WithCompressionThreshold(minSize int) Option {
+	return func(c *CustomHTTPClient) {
+		c.compressionThreshold = minSize
+	}
+}
+
+// WithGzip asks the server for gzip-compressed responses, which are decompressed transparently.
+// View and _all_docs responses are highly repetitive JSON and usually shrink to a fraction of their size,
+// so this cuts bandwidth significantly when the server is far away. To also compress large request bodies,
+// such as bulk updates, combine it with WithCompressionThreshold.
+//
+// Example:
+//
+//	cs := couchdb.GetInstance(url, user, password, couchdb.WithGzip(), couchdb.WithCompressionThreshold(16*1024))
+func WithGzip() Option {
+	return func(c *CustomHTTPClient) {
+		c.gzipResponses = true
+	}
+}
+
+// WithMaxResponseSize limits the size of the response bodies read into memory to maxSize bytes, once decompressed.
+// Requests whose response is larger fail with ErrResponseTooLarge instead of exhausting the memory of the application,
+// e.g. when an unbounded view query or a huge document is requested by mistake. Streamed responses, such as those
+// read by ViewRows or Changes, are not limited, as they are never held in memory as a whole.
+// A non-positive maxSize disables the limit, which is the default.
+//
+// Example:
+//
+//	cs := couchdb.GetInstance(url, user, password, couchdb.WithMaxResponseSize(64<<20))
+func WithMaxResponseSize(maxSize int64) Option {
+	return func(c *CustomHTTPClient) {
+		c.maxResponseSize = maxSize
+	}
+}
+
+// WithMaintenanceRetryWait sets how long to wait before retrying a request that failed because the database was
+// being compacted or resharded. These failures usually last much longer than a generic server error, so they use
+// their own, longer wait instead of the regular retry interval. It defaults to 15 seconds.
+func WithMaintenanceRetryWait(wait time.Duration) Option {
+	return func(c *CustomHTTPClient) {
+		c.maintenanceRetryWait = wait
+	}
 }
 
 // NewCustomHTTPClient creates a new CustomHTTPClient with the specified base URL and configuration options.
 // It returns a pointer to the created CustomHTTPClient instance.
-func NewCustomHTTPClient(baseURL string, maxRetries int, retryWait, timeout time.Duration) *CustomHTTPClient {
-	return &CustomHTTPClient{
+// Most callers should use New instead, which validates the URL and applies the credentials.
+func NewCustomHTTPClient(baseURL string, maxRetries int, retryWait, timeout time.Duration, opts ...Option) *CustomHTTPClient {
+	c := &CustomHTTPClient{
 		baseURL:    baseURL,
 		client:     &http.Client{},
 		maxRetries: maxRetries,
 		retryWait:  retryWait,
 		timeout:    timeout,
+		backoff:    defaultBackoff,
+
+		maintenanceRetryWait: defaultMaintenanceRetryWait,
+		upsertAttempts:       defaultUpsertAttempts,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-// makeRequest makes an HTTP request with the provided method, endpoint, and body.
-// It handles retries according to the configured settings.
-// The function returns the response status code, body, and any error encountered.
-func (c *CustomHTTPClient) makeRequest(ctx context.Context, method, endpoint string, body interface{}) (int, []byte, error) {
-	url := c.baseURL + endpoint
-
-	var reqBody []byte
-	if body != nil {
-		var err error
-		reqBody, err = json.Marshal(body)
+// applyCredentials hands the credentials set by WithAuth to the authentication scheme selected by the options,
+// or embeds them in the base URL if there is none.
+func (c *CustomHTTPClient) applyCredentials() error {
+	switch auth := c.auth.(type) {
+	case *basicAuth:
+		auth.username = c.username
+		auth.password = c.password
+	case *cookieSession:
+		auth.username = c.username
+		auth.password = c.password
+	case nil:
+		authenticatedURL, err := formAuthenticatedURL(c.baseURL, c.username, c.password)
 		if err != nil {
-			return 0, nil, err
+			return err
+		}
+		c.baseURL = authenticatedURL
+	}
+	return nil
+}
+
+// request describes a single call made through the CustomHTTPClient.
+// Content negotiation for both directions is derived from it in one place (see encodeBody and acceptHeader),
+// so endpoints that exchange other media types only need to set the corresponding field.
+type request struct {
+	method      string
+	endpoint    string
+	body        interface{}   // Request body, encoded as JSON; nil sends no body
+	bodyReader  io.Reader     // Request body streamed as-is instead of body, e.g. attachment data; attempted only once
+	contentType string        // Media type of bodyReader; empty means application/json
+	accept      string        // Media type requested for the response; empty means application/json
+	timeout     time.Duration // Timeout of each attempt; zero means the client timeout
+	header      http.Header   // Additional headers set by RequestOptions
+	query       url.Values    // Additional query parameters set by RequestOptions
+	capture     *ResponseInfo // Receives the metadata of the final response, if set by CaptureResponse
+	noRetry     bool          // Attempt the request only once, e.g. for health checks that must answer quickly
+	noTrace     bool          // Never log the bodies, e.g. because they hold secrets that can't be redacted by field
+}
+
+// response holds the outcome of a request whose body has been read completely.
+type response struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// contentTypeHeader returns the value of the Content-Type header to send for the request.
+func (r *request) contentTypeHeader() string {
+	if r.contentType != "" {
+		return r.contentType
+	}
+	return mediaTypeJSON
+}
+
+// acceptHeader returns the value of the Accept header to send for the request.
+func (r *request) acceptHeader() string {
+	if r.accept != "" {
+		return r.accept
+	}
+	return mediaTypeJSON
+}
+
+// encodeBody serializes a request body as JSON, gzip-compressing it when it reaches the configured threshold.
+// It returns the encoded bytes along with the value for the Content-Encoding header, which is empty when
+// the body is sent uncompressed.
+func (c *CustomHTTPClient) encodeBody(body interface{}) ([]byte, string, error) {
+	if body == nil {
+		return nil, "", nil
+	}
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if c.compressionThreshold <= 0 || len(encoded) < c.compressionThreshold {
+		return encoded, "", nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(encoded); err != nil {
+		return nil, "", err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "gzip", nil
+}
+
+// do sends the request, handling retries according to the configured settings.
+// Transport errors, 429 and 5xx responses are retried after an exponentially growing wait, or after the time
+// requested by the server in a Retry-After header, while failures caused by compaction or resharding are retried
+// after the longer maintenance wait. Any other response is returned as-is.
+func (c *CustomHTTPClient) do(ctx context.Context, r *request) (*response, error) {
+	reqBody, contentEncoding, err := c.encodeBody(r.body)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := max(c.maxRetries, 1)
+	if r.noRetry || r.bodyReader != nil {
+		attempts = 1
+	}
+	authRenewed := false
+	firstAttempt := time.Now()
+
+	for i := 1; ; i++ {
+		if c.breaker != nil && !c.breaker.allow() {
+			return nil, ErrCircuitOpen
+		}
+
+		start := time.Now()
+		resp, err := c.send(ctx, r, reqBody, contentEncoding)
+		if err != nil && ctx.Err() != nil {
+			if c.breaker != nil {
+				c.breaker.cancel()
+			}
+			c.logAttempt(ctx, r, i, start, nil, err, false)
+			return nil, err
 		}
+		if errors.Is(err, ErrResponseTooLarge) {
+			// The server did answer, so this is no sign of it failing, and a retry would get the same response.
+			if c.breaker != nil {
+				c.breaker.record(resp.statusCode, nil)
+			}
+			c.logAttempt(ctx, r, i, start, resp, err, false)
+			return nil, err
+		}
+		if c.breaker != nil {
+			if err != nil {
+				c.breaker.record(0, err)
+			} else {
+				c.breaker.record(resp.statusCode, nil)
+			}
+		}
+
+		// Expired credentials are renewed and the request retried once, regardless of the remaining attempts.
+		// A streamed body has been consumed by the first attempt, so it can't be sent again.
+		if refresher, ok := c.auth.(Refresher); ok && err == nil && resp.statusCode == http.StatusUnauthorized && !authRenewed && r.bodyReader == nil {
+			c.logAttempt(ctx, r, i, start, resp, nil, true)
+			if err := refresher.Refresh(ctx); err != nil {
+				return nil, fmt.Errorf("error refreshing credentials: %w", err)
+			}
+			authRenewed = true
+			i--
+			continue
+		}
+
+		wait, retry := c.retryDecision(r, i, resp, err)
+		c.logAttempt(ctx, r, i, start, resp, err, retry)
+
+		if !retry || i >= attempts || c.backoff.exhausted(firstAttempt, wait) {
+			return resp, err
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDecision returns whether, and after how long, to retry the given attempt of the request.
+// The decision of the built-in policy can be overridden by the policy set with WithRetryPolicy.
+func (c *CustomHTTPClient) retryDecision(r *request, i int, resp *response, err error) (time.Duration, bool) {
+	attempt := RetryAttempt{Method: r.method, Attempt: i, Err: err}
+	if resp != nil {
+		attempt.StatusCode, attempt.Header, attempt.Body = resp.statusCode, resp.header, resp.body
 	}
 
-	var respBody []byte
-	var respCode int
-	for i := 0; i < c.maxRetries; i++ {
-		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(reqBody))
+	attempt.DefaultWait = c.backoff.wait(c.retryWait, i)
+	switch {
+	case err != nil:
+		attempt.DefaultRetry = true
+	case isMaintenanceError(resp.statusCode, resp.body):
+		attempt.DefaultWait, attempt.DefaultRetry = c.maintenanceRetryWait, true
+	case resp.statusCode == http.StatusTooManyRequests || resp.statusCode == http.StatusServiceUnavailable:
+		if after, ok := retryAfter(resp.header); ok {
+			attempt.DefaultWait = after
+		}
+		attempt.DefaultRetry = true
+	case resp.statusCode >= 500:
+		attempt.DefaultRetry = true
+	}
+
+	if c.retryPolicy != nil {
+		return c.retryPolicy.Retry(attempt)
+	}
+	return attempt.DefaultWait, attempt.DefaultRetry
+}
+
+// send performs a single attempt of the request, bounded by the configured timeout, and reads the whole response body.
+// If the body exceeds the configured maximum size, the response is returned without it along with ErrResponseTooLarge.
+func (c *CustomHTTPClient) send(ctx context.Context, r *request, body []byte, contentEncoding string) (*response, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	timeout := c.timeout
+	if r.timeout > 0 {
+		timeout = r.timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := c.newHTTPRequest(ctx, r, body, contentEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpDo(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { drainAndClose(resp.Body) }()
+
+	if updater, ok := c.auth.(cookieUpdater); ok {
+		updater.update(resp.Cookies())
+	}
+	decodeResponseBody(resp)
+
+	respBody, err := c.readBody(resp.Body)
+	if errors.Is(err, ErrResponseTooLarge) {
+		return &response{statusCode: resp.StatusCode, header: resp.Header}, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &response{
+		statusCode: resp.StatusCode,
+		header:     resp.Header,
+		body:       respBody,
+	}, nil
+}
+
+// readBody reads a whole response body, failing with ErrResponseTooLarge if it exceeds the configured maximum size.
+func (c *CustomHTTPClient) readBody(body io.Reader) ([]byte, error) {
+	if c.maxResponseSize <= 0 {
+		return io.ReadAll(body)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, c.maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > c.maxResponseSize {
+		return nil, fmt.Errorf("%w: body exceeds %d bytes", ErrResponseTooLarge, c.maxResponseSize)
+	}
+	return data, nil
+}
+
+// drainAndClose reads what is left of a response body, up to maxDrainSize bytes, and closes it,
+// so that the connection is returned to the pool instead of being torn down.
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, maxDrainSize)
+	body.Close()
+}
+
+// stream sends the request and returns the response with its body unread, for endpoints that keep
+// the connection open, such as continuous feeds. Unlike do, it is neither retried nor bound by the client timeout,
+// so the lifetime of the connection is controlled by ctx; only expired credentials are renewed and the request sent
+// again once, as do does. The caller must close the response body.
+func (c *CustomHTTPClient) stream(ctx context.Context, r *request) (*http.Response, error) {
+	reqBody, contentEncoding, err := c.encodeBody(r.body)
+	if err != nil {
+		return nil, err
+	}
+
+	authRenewed := false
+	for i := 1; ; i++ {
+		req, err := c.newHTTPRequest(ctx, r, reqBody, contentEncoding)
 		if err != nil {
-			return 0, nil, err
+			return nil, err
 		}
 
-		req.Header.Set("Content-Type", "application/json")
+		if c.breaker != nil && !c.breaker.allow() {
+			return nil, ErrCircuitOpen
+		}
 
-		ctx, cancel := context.WithTimeout(req.Context(), c.timeout)
-		defer cancel()
-		req = req.WithContext(ctx)
+		release, err := c.acquire(ctx)
+		if err != nil {
+			if c.breaker != nil {
+				c.breaker.cancel()
+			}
+			return nil, err
+		}
 
-		resp, err := c.client.Do(req)
+		start := time.Now()
+		resp, err := c.httpDo(req)
+		release()
+		if c.breaker != nil {
+			switch {
+			case err != nil && ctx.Err() != nil:
+				c.breaker.cancel()
+			case err != nil:
+				c.breaker.record(0, err)
+			default:
+				c.breaker.record(resp.StatusCode, nil)
+			}
+		}
 		if err != nil {
-			if i == c.maxRetries-1 {
-				return 0, nil, err
+			c.logAttempt(ctx, r, i, start, nil, err, false)
+			return nil, err
+		}
+
+		if updater, ok := c.auth.(cookieUpdater); ok {
+			updater.update(resp.Cookies())
+		}
+		decodeResponseBody(resp)
+
+		refresher, ok := c.auth.(Refresher)
+		if ok && resp.StatusCode == http.StatusUnauthorized && !authRenewed && r.bodyReader == nil {
+			c.logAttempt(ctx, r, i, start, &response{statusCode: resp.StatusCode, header: resp.Header}, nil, true)
+			drainAndClose(resp.Body)
+			if err := refresher.Refresh(ctx); err != nil {
+				return nil, fmt.Errorf("error refreshing credentials: %w", err)
 			}
-			time.Sleep(c.retryWait)
+			authRenewed = true
+			i--
 			continue
 		}
-		defer resp.Body.Close()
 
-		respBody, err = io.ReadAll(resp.Body)
-		if err != nil {
-			return 0, nil, err
+		c.logAttempt(ctx, r, i, start, &response{statusCode: resp.StatusCode, header: resp.Header}, nil, false)
+		return resp, nil
+	}
+}
+
+// newHTTPRequest builds the HTTP request for r, setting the content negotiation and authentication headers.
+func (c *CustomHTTPClient) newHTTPRequest(ctx context.Context, r *request, body []byte, contentEncoding string) (*http.Request, error) {
+	reqURL := c.baseURL + r.endpoint
+	if len(r.query) > 0 {
+		sep := "?"
+		if strings.Contains(r.endpoint, "?") {
+			sep = "&"
 		}
+		reqURL += sep + r.query.Encode()
+	}
 
-		respCode = resp.StatusCode
+	var reqBody io.Reader = bytes.NewReader(body)
+	if r.bodyReader != nil {
+		reqBody = r.bodyReader
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, reqURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
 
-		if respCode < 500 {
-			break
+	req.Header.Set("Content-Type", r.contentTypeHeader())
+	req.Header.Set("Accept", r.acceptHeader())
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	if c.gzipResponses {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+
+	if c.auth != nil {
+		if err := c.auth.Authenticate(req); err != nil {
+			return nil, err
 		}
-		time.Sleep(c.retryWait)
 	}
-	return respCode, respBody, nil
+	return req, nil
+}
+
+// decodeResponseBody replaces the body of a gzip-compressed response with its decompressed content.
+// Responses the transport has already decompressed no longer carry the Content-Encoding header and are left as-is,
+// as are responses without a body, such as those of HEAD requests, whose headers describe the content they would have.
+func decodeResponseBody(resp *http.Response) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return
+	}
+	if (resp.Request != nil && resp.Request.Method == "HEAD") || resp.Body == http.NoBody || resp.ContentLength == 0 {
+		return
+	}
+	resp.Body = &gzipBody{body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// gzipBody decompresses a response body. The gzip reader is created on the first read,
+// so that empty bodies, such as those of HEAD responses, don't fail on a missing gzip header.
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.zr == nil && b.err == nil {
+		b.zr, b.err = gzip.NewReader(b.body)
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.zr.Read(p)
+}
+
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
+
+// makeRequest makes an HTTP request with the provided method, endpoint, and body.
+// It handles retries according to the configured settings, and applies opts to the request.
+// The function returns the response status code, body, and any error encountered.
+func (c *CustomHTTPClient) makeRequest(ctx context.Context, method, endpoint string, body interface{}, opts ...RequestOption) (int, []byte, error) {
+	r := &request{method: method, endpoint: endpoint, body: body}
+	for _, opt := range opts {
+		opt(r)
+	}
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if r.capture != nil {
+		r.capture.set(resp)
+	}
+	return resp.statusCode, resp.body, nil
+}
+
+// isMaintenanceError reports whether a failed response was caused by a transient maintenance operation,
+// such as a running compaction or a shard split, based on the error body returned by CouchDB.
+// Only the server errors CouchDB answers with during maintenance qualify: a client error is never retried,
+// whatever its reason says.
+func isMaintenanceError(statusCode int, body []byte) bool {
+	if statusCode != http.StatusInternalServerError && statusCode != http.StatusServiceUnavailable {
+		return false
+	}
+
+	var couchErr struct {
+		Error  string `json:"error"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(body, &couchErr); err != nil {
+		return false
+	}
+
+	text := strings.ToLower(couchErr.Error + " " + couchErr.Reason)
+	for _, marker := range maintenanceMarkers {
+		if strings.Contains(text, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// sleepContext pauses for the given duration or until the context is done, whichever happens first.
+// It returns the context error if the wait was interrupted.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // Get sends a GET request to the specified endpoint with optional request body.
 // It returns the response status code, body, and any error encountered.
-func (c *CustomHTTPClient) Get(ctx context.Context, endpoint string) (int, []byte, error) {
-	return c.makeRequest(ctx, "GET", endpoint, nil)
+func (c *CustomHTTPClient) Get(ctx context.Context, endpoint string, opts ...RequestOption) (int, []byte, error) {
+	return c.makeRequest(ctx, "GET", endpoint, nil, opts...)
 }
 
 // Post sends a POST request to the specified endpoint with the provided body.
 // It returns the response status code, body, and any error encountered.
-func (c *CustomHTTPClient) Post(ctx context.Context, endpoint string, body interface{}) (int, []byte, error) {
-	return c.makeRequest(ctx, "POST", endpoint, body)
+func (c *CustomHTTPClient) Post(ctx context.Context, endpoint string, body interface{}, opts ...RequestOption) (int, []byte, error) {
+	return c.makeRequest(ctx, "POST", endpoint, body, opts...)
 }
 
 // Put sends a PUT request to the specified endpoint with the provided body.
 // It returns the response status code, body, and any error encountered.
-func (c *CustomHTTPClient) Put(ctx context.Context, endpoint string, body interface{}) (int, []byte, error) {
-	return c.makeRequest(ctx, "PUT", endpoint, body)
+func (c *CustomHTTPClient) Put(ctx context.Context, endpoint string, body interface{}, opts ...RequestOption) (int, []byte, error) {
+	return c.makeRequest(ctx, "PUT", endpoint, body, opts...)
 }
 
 // Delete sends a DELETE request to the specified endpoint.
 // It returns the response status code, body, and any error encountered.
-func (c *CustomHTTPClient) Delete(ctx context.Context, endpoint string) (int, []byte, error) {
-	return c.makeRequest(ctx, "DELETE", endpoint, nil)
+func (c *CustomHTTPClient) Delete(ctx context.Context, endpoint string, opts ...RequestOption) (int, []byte, error) {
+	return c.makeRequest(ctx, "DELETE", endpoint, nil, opts...)
 }
 
 // Head sends a HEAD request to the specified endpoint.
 // It returns the response status code, body, and any error encountered.
-func (c *CustomHTTPClient) Head(ctx context.Context, endpoint string) (int, []byte, error) {
-	return c.makeRequest(ctx, "HEAD", endpoint, nil)
+func (c *CustomHTTPClient) Head(ctx context.Context, endpoint string, opts ...RequestOption) (int, []byte, error) {
+	return c.makeRequest(ctx, "HEAD", endpoint, nil, opts...)
 }
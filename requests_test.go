@@ -0,0 +1,278 @@
+package couchdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEncodeBody(t *testing.T) {
+	testCases := []struct {
+		name             string
+		threshold        int
+		body             interface{}
+		expectedEncoding string
+	}{
+		{name: "nil body", threshold: 1, body: nil, expectedEncoding: ""},
+		{name: "compression disabled", threshold: 0, body: map[string]string{"key": "value"}, expectedEncoding: ""},
+		{name: "body below threshold", threshold: 1024, body: map[string]string{"key": "value"}, expectedEncoding: ""},
+		{name: "body above threshold", threshold: 8, body: map[string]string{"key": "value"}, expectedEncoding: "gzip"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &CustomHTTPClient{compressionThreshold: tc.threshold}
+			encoded, encoding, err := c.encodeBody(tc.body)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if encoding != tc.expectedEncoding {
+				t.Errorf("Expected encoding %q, got %q", tc.expectedEncoding, encoding)
+			}
+			if tc.body == nil {
+				if encoded != nil {
+					t.Errorf("Expected no body, got %q", encoded)
+				}
+				return
+			}
+
+			if encoding == "gzip" {
+				zr, err := gzip.NewReader(bytes.NewReader(encoded))
+				if err != nil {
+					t.Fatalf("Unexpected error opening gzip body: %v", err)
+				}
+				encoded, err = io.ReadAll(zr)
+				if err != nil {
+					t.Fatalf("Unexpected error reading gzip body: %v", err)
+				}
+			}
+			if string(encoded) != `{"key":"value"}` {
+				t.Errorf("Unexpected body: %s", encoded)
+			}
+		})
+	}
+}
+
+func TestAcceptHeader(t *testing.T) {
+	testCases := []struct {
+		accept   string
+		expected string
+	}{
+		{"", "application/json"},
+		{"multipart/related", "multipart/related"},
+	}
+
+	for _, tc := range testCases {
+		r := &request{accept: tc.accept}
+		if got := r.acceptHeader(); got != tc.expected {
+			t.Errorf("Expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestIsMaintenanceError(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{name: "compaction in progress", statusCode: 500, body: `{"error":"database_compaction","reason":"compaction in progress"}`, expected: true},
+		{name: "shard split", statusCode: 503, body: `{"error":"shard_split_in_progress","reason":"resharding"}`, expected: true},
+		{name: "reason mentions resharding", statusCode: 500, body: `{"error":"unknown_error","reason":"Resharding job running"}`, expected: true},
+		{name: "client error mentioning resharding", statusCode: 409, body: `{"error":"unknown_error","reason":"Resharding job running"}`, expected: false},
+		{name: "bad request mentioning compaction", statusCode: 400, body: `{"error":"bad_request","reason":"invalid compaction option"}`, expected: false},
+		{name: "generic server error", statusCode: 500, body: `{"error":"unknown_error","reason":"timeout"}`, expected: false},
+		{name: "document conflict", statusCode: 409, body: `{"error":"conflict","reason":"Document update conflict."}`, expected: false},
+		{name: "non JSON body", statusCode: 502, body: `Bad Gateway`, expected: false},
+		{name: "successful response", statusCode: 200, body: `{"reason":"compaction"}`, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isMaintenanceError(tc.statusCode, []byte(tc.body)); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestMaintenanceRetries(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statusCode       int
+		body             string
+		expectedRequests int32
+	}{
+		{name: "compaction in progress", statusCode: 500, body: `{"error":"database_compaction","reason":"compaction in progress"}`, expectedRequests: 3},
+		{name: "bad request mentioning compaction", statusCode: 400, body: `{"error":"bad_request","reason":"invalid compaction option"}`, expectedRequests: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			client := NewCustomHTTPClient(server.URL+"/", 3, time.Millisecond, time.Second, WithMaintenanceRetryWait(time.Millisecond))
+			code, _, err := client.Get(context.Background(), "test")
+			if err != nil || code != tc.statusCode {
+				t.Fatalf("Expected %d, got %d, %v", tc.statusCode, code, err)
+			}
+			if requests != tc.expectedRequests {
+				t.Errorf("Expected %d requests, got %d", tc.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestWithGzip(t *testing.T) {
+	const body = `{"total_rows":1,"offset":0,"rows":[{"id":"a","key":"a","value":null}]}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.Write([]byte(body))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(body))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "gzip disabled", opts: nil},
+		{name: "gzip enabled", opts: []Option{WithGzip()}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second, tc.opts...)
+			code, respBody, err := client.Get(context.Background(), "test/_all_docs")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if code != 200 || string(respBody) != body {
+				t.Errorf("Expected the decompressed body, got %d - %q", code, respBody)
+			}
+		})
+	}
+}
+
+func TestWithGzipKeepsHeadHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", "123")
+		w.Header().Set("ETag", `"1-abc"`)
+	}))
+	defer server.Close()
+
+	client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second, WithGzip())
+
+	var info ResponseInfo
+	if _, _, err := client.Head(context.Background(), "test/doc/file.txt", CaptureResponse(&info)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.Header.Get("Content-Encoding") != "gzip" || info.Header.Get("Content-Length") != "123" || info.ETag != "1-abc" {
+		t.Errorf("Expected the headers of the HEAD response to be left as-is, got %v", info.Header)
+	}
+}
+
+func TestWithMaxResponseSize(t *testing.T) {
+	body := strings.Repeat("a", 100)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Accept-Encoding") == "gzip" {
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			zw.Write([]byte(body))
+			zw.Close()
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name string
+		opts []Option
+		err  error
+	}{
+		{name: "no limit"},
+		{name: "body within limit", opts: []Option{WithMaxResponseSize(100)}},
+		{name: "body over limit", opts: []Option{WithMaxResponseSize(99)}, err: ErrResponseTooLarge},
+		{name: "decompressed body over limit", opts: []Option{WithGzip(), WithMaxResponseSize(99)}, err: ErrResponseTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests = 0
+			client := NewCustomHTTPClient(server.URL+"/", 3, time.Millisecond, time.Second, tc.opts...)
+			_, respBody, err := client.Get(context.Background(), "test/doc")
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if tc.err == nil && string(respBody) != body {
+				t.Errorf("Expected the whole body, got %q", respBody)
+			}
+			if requests != 1 {
+				t.Errorf("Expected 1 request, got %d", requests)
+			}
+		})
+	}
+}
+
+// trackedBody is a response body recording how it was consumed.
+type trackedBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDrainAndClose(t *testing.T) {
+	testCases := []struct {
+		name      string
+		size      int
+		remaining int
+	}{
+		{name: "empty body", size: 0, remaining: 0},
+		{name: "small body", size: 1024, remaining: 0},
+		{name: "body larger than the drain limit", size: maxDrainSize + 10, remaining: 10},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := strings.NewReader(strings.Repeat("a", tc.size))
+			body := &trackedBody{Reader: reader}
+			drainAndClose(body)
+			if !body.closed {
+				t.Error("Expected the body to be closed")
+			}
+			if reader.Len() != tc.remaining {
+				t.Errorf("Expected %d bytes left unread, got %d", tc.remaining, reader.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// States of resharding, for both the whole node and individual jobs.
+const (
+	ReshardRunning = "running"
+	ReshardStopped = "stopped"
+)
+
+// ReshardSummary is the state of resharding on the cluster, as returned by Resharder.Summary.
+type ReshardSummary struct {
+	State       string `json:"state"`        // ReshardRunning, or ReshardStopped if resharding is disabled
+	StateReason string `json:"state_reason"` // Why resharding was stopped, as given to SetState
+	Completed   int    `json:"completed"`    // Number of completed jobs
+	Failed      int    `json:"failed"`       // Number of failed jobs
+	Running     int    `json:"running"`      // Number of running jobs
+	Stopped     int    `json:"stopped"`      // Number of stopped jobs
+	Total       int    `json:"total"`        // Number of jobs
+}
+
+// ReshardJobRequest describes the shards to split, as given to Resharder.CreateJobs.
+// Either Shard, or DB optionally narrowed by Node and Range, selects the shards.
+type ReshardJobRequest struct {
+	Type  string `json:"type"`            // Type of the job; only "split" is supported, which is the default
+	DB    string `json:"db,omitempty"`    // Split the shards of this database
+	Node  string `json:"node,omitempty"`  // Split only the shards on this node
+	Range string `json:"range,omitempty"` // Split only the shards of this range, e.g. "00000000-7fffffff"
+	Shard string `json:"shard,omitempty"` // Split only this shard copy, e.g. "shards/00000000-7fffffff/orders.1588"
+}
+
+// ReshardJobResult is the outcome of creating one of the jobs requested from Resharder.CreateJobs.
+// Either Ok and ID are set, or Error and Reason describe why the job was not created.
+type ReshardJobResult struct {
+	Ok     bool   `json:"ok,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Node   string `json:"node"`
+	Shard  string `json:"shard"`
+	Error  string `json:"error,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReshardJobEvent is an event in the history of a resharding job.
+type ReshardJobEvent struct {
+	Timestamp string `json:"timestamp"`
+	Type      string `json:"type"` // e.g. "new", "start", "stop", "completed"
+	Detail    string `json:"detail,omitempty"`
+}
+
+// ReshardJob is a resharding job, as returned by Resharder.Jobs.
+type ReshardJob struct {
+	ID          string            `json:"id"`
+	Type        string            `json:"type"`
+	JobState    string            `json:"job_state"`    // "new", "running", "stopped", "completed" or "failed"
+	SplitState  string            `json:"split_state"`  // Step of the split, e.g. "initial_copy" or "completed"
+	State       string            `json:"state"`        // Not "stopped" if the job can run
+	StateReason string            `json:"state_reason"` // Why the job is stopped or failed
+	Source      string            `json:"source"`       // Shard being split
+	Target      []string          `json:"target"`       // Shards created by the split
+	Node        string            `json:"node"`
+	StartTime   string            `json:"start_time"`
+	UpdateTime  string            `json:"update_time"`
+	History     []ReshardJobEvent `json:"history"`
+}
+
+// Resharder manages the splitting of database shards, which increases the number of shards of a database that
+// grew too big for them while it keeps serving requests. It requires server admin privileges.
+type Resharder struct {
+	httpClient *CustomHTTPClient
+}
+
+// Resharder returns the handle for managing the resharding of the cluster.
+//
+// Example:
+//
+//	results, err := cs.Resharder().CreateJobs(ctx, couchdb.ReshardJobRequest{DB: "orders"})
+//	if err != nil {
+//	    log.Fatalf("Error splitting shards: %v", err)
+//	}
+func (c *CouchService) Resharder() *Resharder {
+	return &Resharder{httpClient: c.httpClient}
+}
+
+// Summary returns the state of resharding and the number of jobs by state.
+func (r *Resharder) Summary(ctx context.Context) (*ReshardSummary, error) {
+	var summary ReshardSummary
+	if err := r.get(ctx, "_reshard", &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// SetState starts or stops resharding on the whole cluster, e.g. to stop it during a traffic peak.
+// Stopped jobs resume where they left when resharding is started again.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - state: ReshardRunning or ReshardStopped.
+//   - reason: Why resharding is stopped, reported by Summary; ignored when starting it.
+func (r *Resharder) SetState(ctx context.Context, state, reason string) error {
+	return r.putState(ctx, "_reshard/state", state, reason)
+}
+
+// Jobs returns all the resharding jobs, including the completed and failed ones.
+func (r *Resharder) Jobs(ctx context.Context) ([]ReshardJob, error) {
+	var jobsResponse struct {
+		Jobs []ReshardJob `json:"jobs"`
+	}
+	if err := r.get(ctx, "_reshard/jobs", &jobsResponse); err != nil {
+		return nil, err
+	}
+	return jobsResponse.Jobs, nil
+}
+
+// Job returns a resharding job.
+//
+// Returns:
+//   - *ReshardJob: The job.
+//   - error: ErrNotFound if the job doesn't exist, or any other error encountered.
+func (r *Resharder) Job(ctx context.Context, id string) (*ReshardJob, error) {
+	var job ReshardJob
+	if err := r.get(ctx, fmt.Sprintf("_reshard/jobs/%s", url.PathEscape(id)), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// CreateJobs creates a job to split each of the shards selected by req in two.
+//
+// Returns:
+//   - []ReshardJobResult: One result per selected shard copy.
+//   - error: An error if the request as a whole failed.
+func (r *Resharder) CreateJobs(ctx context.Context, req ReshardJobRequest) ([]ReshardJobResult, error) {
+	if req.Type == "" {
+		req.Type = "split"
+	}
+
+	respCode, respBody, err := r.httpClient.Post(ctx, "_reshard/jobs", req)
+	if err != nil {
+		return nil, fmt.Errorf("error creating reshard jobs: %w", err)
+	}
+
+	if respCode != 201 {
+		return nil, responseError("error creating reshard jobs", respCode, respBody)
+	}
+
+	var results []ReshardJobResult
+	err = json.Unmarshal(respBody, &results)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling reshard jobs response: %w", err)
+	}
+
+	return results, nil
+}
+
+// SetJobState starts or stops a single resharding job.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - id: The ID of the job.
+//   - state: ReshardRunning or ReshardStopped.
+//   - reason: Why the job is stopped; ignored when starting it.
+func (r *Resharder) SetJobState(ctx context.Context, id, state, reason string) error {
+	return r.putState(ctx, fmt.Sprintf("_reshard/jobs/%s/state", url.PathEscape(id)), state, reason)
+}
+
+// DeleteJob removes a resharding job, stopping it if it is running.
+func (r *Resharder) DeleteJob(ctx context.Context, id string) error {
+	respCode, respBody, err := r.httpClient.Delete(ctx, fmt.Sprintf("_reshard/jobs/%s", url.PathEscape(id)))
+	if err != nil {
+		return fmt.Errorf("error deleting reshard job: %w", err)
+	}
+
+	if respCode != 200 {
+		return responseError("error deleting reshard job", respCode, respBody)
+	}
+
+	return nil
+}
+
+// get gets the resharding endpoint and unmarshals the response into result.
+func (r *Resharder) get(ctx context.Context, endpoint string, result any) error {
+	respCode, respBody, err := r.httpClient.Get(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("error getting reshard info: %w", err)
+	}
+
+	if respCode != 200 {
+		return responseError("error getting reshard info", respCode, respBody)
+	}
+
+	err = json.Unmarshal(respBody, result)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling reshard info: %w", err)
+	}
+
+	return nil
+}
+
+// putState sets the state at the given resharding endpoint.
+func (r *Resharder) putState(ctx context.Context, endpoint, state, reason string) error {
+	body := map[string]string{"state": state}
+	if reason != "" && state == ReshardStopped {
+		body["reason"] = reason
+	}
+
+	respCode, respBody, err := r.httpClient.Put(ctx, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("error setting reshard state: %w", err)
+	}
+
+	if respCode != 200 {
+		return responseError("error setting reshard state", respCode, respBody)
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReshardSummaryAndJobs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_reshard":
+			w.Write([]byte(`{"state":"stopped","state_reason":"traffic peak","completed":3,"failed":0,"running":0,"stopped":1,"total":4}`))
+		case "/_reshard/jobs":
+			w.Write([]byte(`{"jobs":[{"id":"001-b3da04","type":"split","job_state":"completed","split_state":"completed","state":"completed","source":"shards/00000000-ffffffff/orders.1588","target":["shards/00000000-7fffffff/orders.1588","shards/80000000-ffffffff/orders.1588"],"node":"couchdb@10.0.0.1","history":[{"timestamp":"2024-05-02T10:00:00Z","type":"new"}]}],"offset":0,"total_rows":1}`))
+		case "/_reshard/jobs/001-b3da04":
+			w.Write([]byte(`{"id":"001-b3da04","type":"split","job_state":"running","split_state":"initial_copy"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not_found","reason":"Job not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	resharder := (&CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}).Resharder()
+
+	summary, err := resharder.Summary(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error getting summary: %v", err)
+	}
+	if summary.State != ReshardStopped || summary.StateReason != "traffic peak" || summary.Total != 4 {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+
+	jobs, err := resharder.Jobs(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error listing jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Source != "shards/00000000-ffffffff/orders.1588" || len(jobs[0].Target) != 2 || len(jobs[0].History) != 1 {
+		t.Errorf("Unexpected jobs %+v", jobs)
+	}
+
+	testCases := []struct {
+		name       string
+		id         string
+		splitState string
+		err        error
+	}{
+		{name: "existing job", id: "001-b3da04", splitState: "initial_copy"},
+		{name: "missing job", id: "002-missing", err: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job, err := resharder.Job(context.Background(), tc.id)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if tc.err == nil && job.SplitState != tc.splitState {
+				t.Errorf("Expected split state %s, got %s", tc.splitState, job.SplitState)
+			}
+		})
+	}
+}
+
+func TestReshardSetState(t *testing.T) {
+	var receivedPath string
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		received = nil
+		if r.Method != "PUT" {
+			t.Errorf("Expected PUT, got %s", r.Method)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		if received["state"] != ReshardRunning && received["state"] != ReshardStopped {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad_request","reason":"State field not a string or not recognized"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	resharder := (&CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}).Resharder()
+
+	testCases := []struct {
+		name         string
+		job          string
+		state        string
+		reason       string
+		expectedPath string
+		expected     map[string]string
+		err          error
+	}{
+		{
+			name:         "stop resharding",
+			state:        ReshardStopped,
+			reason:       "traffic peak",
+			expectedPath: "/_reshard/state",
+			expected:     map[string]string{"state": "stopped", "reason": "traffic peak"},
+		},
+		{
+			name:         "start resharding ignores reason",
+			state:        ReshardRunning,
+			reason:       "ignored",
+			expectedPath: "/_reshard/state",
+			expected:     map[string]string{"state": "running"},
+		},
+		{
+			name:         "stop job",
+			job:          "001-b3da04",
+			state:        ReshardStopped,
+			expectedPath: "/_reshard/jobs/001-b3da04/state",
+			expected:     map[string]string{"state": "stopped"},
+		},
+		{
+			name:         "invalid state",
+			state:        "paused",
+			expectedPath: "/_reshard/state",
+			expected:     map[string]string{"state": "paused"},
+			err:          ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+			if tc.job == "" {
+				err = resharder.SetState(context.Background(), tc.state, tc.reason)
+			} else {
+				err = resharder.SetJobState(context.Background(), tc.job, tc.state, tc.reason)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if receivedPath != tc.expectedPath {
+				t.Errorf("Expected path %s, got %s", tc.expectedPath, receivedPath)
+			}
+			if len(received) != len(tc.expected) {
+				t.Fatalf("Expected body %v, got %v", tc.expected, received)
+			}
+			for k, v := range tc.expected {
+				if received[k] != v {
+					t.Errorf("Expected %s to be %s, got %s", k, v, received[k])
+				}
+			}
+		})
+	}
+}
+
+func TestReshardCreateJobs(t *testing.T) {
+	var received ReshardJobRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/_reshard/jobs" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		received = ReshardJobRequest{}
+		json.NewDecoder(r.Body).Decode(&received)
+		if received.DB == "missing" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad_request","reason":"` + "`db`" + ` is not valid"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`[{"ok":true,"id":"001-b3da04","node":"couchdb@10.0.0.1","shard":"shards/00000000-ffffffff/orders.1588"},{"error":"conflict","reason":"Job already exists","node":"couchdb@10.0.0.2","shard":"shards/00000000-ffffffff/orders.1588"}]`))
+	}))
+	defer server.Close()
+
+	resharder := (&CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}).Resharder()
+
+	testCases := []struct {
+		name    string
+		req     ReshardJobRequest
+		results int
+		err     error
+	}{
+		{name: "split database", req: ReshardJobRequest{DB: "orders"}, results: 2},
+		{name: "invalid database", req: ReshardJobRequest{DB: "missing"}, err: ErrBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := resharder.CreateJobs(context.Background(), tc.req)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if received.Type != "split" {
+				t.Errorf("Expected type split, got %q", received.Type)
+			}
+			if len(results) != tc.results {
+				t.Fatalf("Expected %d results, got %d", tc.results, len(results))
+			}
+			if tc.results > 0 && (!results[0].Ok || results[0].ID != "001-b3da04" || results[1].Error != "conflict") {
+				t.Errorf("Unexpected results %+v", results)
+			}
+		})
+	}
+}
+
+func TestReshardDeleteJob(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("Expected DELETE, got %s", r.Method)
+		}
+		if r.URL.Path != "/_reshard/jobs/001-b3da04" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not_found","reason":"Job not found"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	resharder := (&CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}).Resharder()
+
+	testCases := []struct {
+		name string
+		id   string
+		err  error
+	}{
+		{name: "existing job", id: "001-b3da04"},
+		{name: "missing job", id: "002-missing", err: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := resharder.DeleteJob(context.Background(), tc.id)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
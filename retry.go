@@ -0,0 +1,134 @@
+package couchdb
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultMaxBackoff is the default upper bound of a single wait between retries.
+const defaultMaxBackoff = 30 * time.Second
+
+// Backoff configures how the wait between the attempts of a failed request grows.
+//
+// The n-th retry waits Initial * Multiplier^(n-1), capped at Max, and then shortened by a random fraction
+// of up to Jitter, so that clients retrying against a struggling node don't do it in lockstep.
+type Backoff struct {
+	Initial    time.Duration // Wait before the first retry; 0 means the client retry wait
+	Max        time.Duration // Upper bound of a single wait; 0 means no bound
+	Multiplier float64       // Growth factor of the wait after each retry; values below 1 mean 2
+	Jitter     float64       // Fraction of each wait that is randomized, between 0 (none) and 1 (full jitter)
+	MaxElapsed time.Duration // Time since the first attempt after which a request is no longer retried; 0 means no limit
+}
+
+// defaultBackoff is the backoff of new clients, which doubles the retry wait after each retry.
+var defaultBackoff = Backoff{Max: defaultMaxBackoff, Multiplier: 2, Jitter: 0.5}
+
+// WithBackoff sets how the wait between retries grows. By default, it starts at the retry wait, doubles after
+// each retry up to 30 seconds, and is randomized by up to half its length.
+//
+// Example:
+//
+//	cs, err := couchdb.New(url, couchdb.WithRetries(8), couchdb.WithBackoff(couchdb.Backoff{
+//	    Initial:    100 * time.Millisecond,
+//	    Max:        5 * time.Second,
+//	    Multiplier: 2,
+//	    Jitter:     1,
+//	    MaxElapsed: 20 * time.Second,
+//	}))
+func WithBackoff(backoff Backoff) Option {
+	return func(c *CustomHTTPClient) {
+		c.backoff = backoff
+	}
+}
+
+// RetryAttempt describes a failed or successful attempt of a request, for a RetryPolicy to decide whether to retry it.
+type RetryAttempt struct {
+	Method     string      // HTTP method of the request
+	Attempt    int         // Number of the attempt, counting from 1
+	StatusCode int         // Status code of the response; 0 if there is none because of Err
+	Header     http.Header // Headers of the response; nil if there is none
+	Body       []byte      // Body of the response, e.g. to inspect the CouchDB error; nil if there is none
+	Err        error       // Transport error of the attempt, if any
+
+	// DefaultWait and DefaultRetry are the decision of the built-in policy, so a custom policy can refine it
+	// instead of reimplementing it.
+	DefaultWait  time.Duration
+	DefaultRetry bool
+}
+
+// RetryPolicy decides whether to retry an attempt of a request and how long to wait before doing so.
+// Regardless of the policy, requests are attempted at most as many times as set by WithRetries, and never
+// past the maximum elapsed time of the Backoff.
+type RetryPolicy interface {
+	Retry(attempt RetryAttempt) (wait time.Duration, retry bool)
+}
+
+// RetryPolicyFunc adapts a function to a RetryPolicy.
+type RetryPolicyFunc func(attempt RetryAttempt) (time.Duration, bool)
+
+// Retry calls f(attempt).
+func (f RetryPolicyFunc) Retry(attempt RetryAttempt) (time.Duration, bool) {
+	return f(attempt)
+}
+
+// WithRetryPolicy replaces the built-in retry decisions with those of policy. By default, transport errors,
+// 429 and 5xx responses are retried, whatever the method of the request.
+//
+// Example:
+//
+//	// Never retry POST requests, which CouchDB may have applied before failing, e.g. document creations.
+//	cs, err := couchdb.New(url, couchdb.WithRetryPolicy(couchdb.RetryPolicyFunc(
+//	    func(attempt couchdb.RetryAttempt) (time.Duration, bool) {
+//	        return attempt.DefaultWait, attempt.DefaultRetry && attempt.Method != http.MethodPost
+//	    },
+//	)))
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *CustomHTTPClient) {
+		c.retryPolicy = policy
+	}
+}
+
+// wait returns how long to wait before the given retry, counting from 1, when the first wait is initial.
+func (b Backoff) wait(initial time.Duration, retry int) time.Duration {
+	if b.Initial > 0 {
+		initial = b.Initial
+	}
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	wait := float64(initial) * math.Pow(multiplier, float64(retry-1))
+	if b.Max > 0 && wait > float64(b.Max) {
+		wait = float64(b.Max)
+	}
+	if jitter := min(max(b.Jitter, 0), 1); jitter > 0 {
+		wait -= wait * jitter * rand.Float64()
+	}
+	return time.Duration(wait)
+}
+
+// exhausted reports whether a request first attempted at start can no longer wait the given time before retrying.
+func (b Backoff) exhausted(start time.Time, wait time.Duration) bool {
+	return b.MaxElapsed > 0 && time.Since(start)+wait > b.MaxElapsed
+}
+
+// retryAfter parses the Retry-After header of a response, given either as a number of seconds or as an HTTP date.
+// It reports false if the header is missing or invalid.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,161 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackoffWait(t *testing.T) {
+	testCases := []struct {
+		name    string
+		backoff Backoff
+		retry   int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{name: "first retry uses the client wait", backoff: Backoff{Multiplier: 2}, retry: 1, min: time.Second, max: time.Second},
+		{name: "wait grows exponentially", backoff: Backoff{Multiplier: 2}, retry: 4, min: 8 * time.Second, max: 8 * time.Second},
+		{name: "explicit initial wait", backoff: Backoff{Initial: 100 * time.Millisecond, Multiplier: 3}, retry: 3, min: 900 * time.Millisecond, max: 900 * time.Millisecond},
+		{name: "invalid multiplier means 2", backoff: Backoff{Multiplier: 0.5}, retry: 3, min: 4 * time.Second, max: 4 * time.Second},
+		{name: "wait is capped", backoff: Backoff{Multiplier: 2, Max: 5 * time.Second}, retry: 10, min: 5 * time.Second, max: 5 * time.Second},
+		{name: "jitter shortens the wait", backoff: Backoff{Multiplier: 2, Jitter: 0.5}, retry: 2, min: time.Second, max: 2 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				wait := tc.backoff.wait(time.Second, tc.retry)
+				if wait < tc.min || wait > tc.max {
+					t.Fatalf("Expected a wait between %v and %v, got %v", tc.min, tc.max, wait)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffMaxElapsed(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewCustomHTTPClient(server.URL+"/", 10, 20*time.Millisecond, time.Second,
+		WithBackoff(Backoff{Multiplier: 2, MaxElapsed: 100 * time.Millisecond}))
+
+	code, _, err := client.Get(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if code != http.StatusInternalServerError {
+		t.Errorf("Expected the last response to be returned, got %d", code)
+	}
+	// Waits of 20, 40 and 80ms exceed the maximum elapsed time after the third attempt.
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "missing header", value: "", ok: false},
+		{name: "seconds", value: "3", expected: 3 * time.Second, ok: true},
+		{name: "negative seconds", value: "-1", expected: 0, ok: true},
+		{name: "date in the past", value: "Wed, 21 Oct 2015 07:28:00 GMT", expected: 0, ok: true},
+		{name: "invalid value", value: "soon", ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			if tc.value != "" {
+				header.Set("Retry-After", tc.value)
+			}
+			wait, ok := retryAfter(header)
+			if ok != tc.ok || wait != tc.expected {
+				t.Errorf("Expected %v, %v, got %v, %v", tc.expected, tc.ok, wait, ok)
+			}
+		})
+	}
+}
+
+func TestRetryAfterIsHonored(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if attempts == 1 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			// The regular wait would outlast the context, so the retry only succeeds if Retry-After is used.
+			client := NewCustomHTTPClient(server.URL+"/", 2, time.Minute, time.Second)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			code, _, err := client.Get(ctx, "test")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if code != http.StatusOK || attempts != 2 {
+				t.Errorf("Expected a successful retry, got %d after %d attempts", code, attempts)
+			}
+		})
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	var attempts map[string]int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts[r.Method]++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	noPostRetries := RetryPolicyFunc(func(attempt RetryAttempt) (time.Duration, bool) {
+		return attempt.DefaultWait, attempt.DefaultRetry && attempt.Method != http.MethodPost
+	})
+	retryConflicts := RetryPolicyFunc(func(attempt RetryAttempt) (time.Duration, bool) {
+		return time.Millisecond, attempt.StatusCode == http.StatusConflict
+	})
+
+	testCases := []struct {
+		name     string
+		policy   RetryPolicy
+		method   string
+		expected int
+	}{
+		{name: "built-in policy retries POST", policy: nil, method: http.MethodPost, expected: 3},
+		{name: "custom policy skips POST", policy: noPostRetries, method: http.MethodPost, expected: 1},
+		{name: "custom policy keeps GET retries", policy: noPostRetries, method: http.MethodGet, expected: 3},
+		{name: "custom policy ignores server errors", policy: retryConflicts, method: http.MethodGet, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts = map[string]int{}
+			client := NewCustomHTTPClient(server.URL+"/", 3, time.Millisecond, time.Second, WithRetryPolicy(tc.policy))
+			if _, _, err := client.makeRequest(context.Background(), tc.method, "test", nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if attempts[tc.method] != tc.expected {
+				t.Errorf("Expected %d attempts, got %d", tc.expected, attempts[tc.method])
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// GetRevsLimit returns how many revisions of each document the database remembers, which defaults to 1000.
+// Older revisions are pruned from the revision tree on compaction.
+func (db *Database) GetRevsLimit(ctx context.Context, opts ...RequestOption) (int, error) {
+	return db.getLimit(ctx, "_revs_limit", opts...)
+}
+
+// SetRevsLimit sets how many revisions of each document the database remembers.
+// Lowering it saves space on workloads that update the same documents very often, at the cost of replication
+// with a replica that has fallen further behind than the limit, which sees the documents as conflicting.
+//
+// Example:
+//
+//	if err := db.SetRevsLimit(ctx, 100); err != nil {
+//	    log.Fatalf("Error setting revision limit: %v", err)
+//	}
+func (db *Database) SetRevsLimit(ctx context.Context, limit int, opts ...RequestOption) error {
+	return db.setLimit(ctx, "_revs_limit", limit, opts...)
+}
+
+// Revisions is the revision history of a document, as returned with GetDocOptions.Revs.
+type Revisions struct {
+	Start int      `json:"start"` // Generation of the current revision, i.e. the number before its dash
+	IDs   []string `json:"ids"`   // Hashes of the revisions, from the current one back to the oldest one remembered
+}
+
+// Revs returns the full revisions of the history, such as "3-c1a0", from the current one back to the oldest one.
+func (r Revisions) Revs() []string {
+	revs := make([]string, len(r.IDs))
+	for i, id := range r.IDs {
+		revs[i] = fmt.Sprintf("%d-%s", r.Start-i, id)
+	}
+	return revs
+}
+
+// Statuses of a revision in RevInfo.
+const (
+	RevAvailable = "available" // The content of the revision is stored in the database
+	RevMissing   = "missing"   // The content of the revision was removed on compaction
+	RevDeleted   = "deleted"   // The revision deleted the document
+)
+
+// RevInfo is a revision of a document together with its status, as returned with GetDocOptions.RevsInfo.
+type RevInfo struct {
+	Rev    string `json:"rev"`
+	Status string `json:"status"` // One of RevAvailable, RevMissing and RevDeleted
+}
+
+// MissingRevs returns which of the given revisions don't exist in the database, which is how a replicator
+// decides what to send to a target.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - revs: The revisions to check, by document ID.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - map[string][]string: The revisions missing from the database, by document ID.
+//     Documents with no missing revisions are left out.
+//   - error: An error, if any, encountered while checking the revisions.
+//
+// Example:
+//
+//	missing, err := target.MissingRevs(ctx, map[string][]string{"invoice:42": {"3-c1a0", "4-d2f9"}})
+//	if err != nil {
+//	    log.Fatalf("Error checking revisions: %v", err)
+//	}
+func (db *Database) MissingRevs(ctx context.Context, revs map[string][]string, opts ...RequestOption) (map[string][]string, error) {
+	respCode, respBody, err := db.httpClient.Post(ctx, fmt.Sprintf("%s/_missing_revs", db.dbName), revs, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting missing revs: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting missing revs", respCode, respBody)
+	}
+
+	var missingRevsResponse struct {
+		MissingRevs map[string][]string `json:"missing_revs"`
+	}
+	err = json.Unmarshal(respBody, &missingRevsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling missing revs response: %w", err)
+	}
+
+	return missingRevsResponse.MissingRevs, nil
+}
+
+// RevsDiff is the difference between a set of revisions of a document and those in the database, as returned by RevsDiff.
+type RevsDiff struct {
+	Missing           []string `json:"missing"`                      // Revisions that don't exist in the database
+	PossibleAncestors []string `json:"possible_ancestors,omitempty"` // Leaf revisions in the database that may be ancestors of the missing ones
+}
+
+// RevsDiff returns which of the given revisions don't exist in the database, along with the revisions that
+// may be their ancestors, so a sync protocol can send only the history the database doesn't have.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - revs: The revisions to compare, by document ID.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - map[string]RevsDiff: The difference for each document with missing revisions, by document ID.
+//   - error: An error, if any, encountered while comparing the revisions.
+//
+// Example:
+//
+//	diff, err := target.RevsDiff(ctx, map[string][]string{"invoice:42": {"3-c1a0", "4-d2f9"}})
+//	if err != nil {
+//	    log.Fatalf("Error comparing revisions: %v", err)
+//	}
+//	for id, d := range diff {
+//	    fmt.Println(id, d.Missing, d.PossibleAncestors)
+//	}
+func (db *Database) RevsDiff(ctx context.Context, revs map[string][]string, opts ...RequestOption) (map[string]RevsDiff, error) {
+	respCode, respBody, err := db.httpClient.Post(ctx, fmt.Sprintf("%s/_revs_diff", db.dbName), revs, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting revs diff: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting revs diff", respCode, respBody)
+	}
+
+	var diff map[string]RevsDiff
+	err = json.Unmarshal(respBody, &diff)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling revs diff response: %w", err)
+	}
+
+	return diff, nil
+}
+
+// OpenRev is a leaf revision of a document, as returned by GetOpenRevs.
+type OpenRev struct {
+	Ok      json.RawMessage `json:"ok,omitempty"`      // The document at this revision, if it exists
+	Missing string          `json:"missing,omitempty"` // The requested revision, if it doesn't exist
+}
+
+// GetOpenRevs retrieves the given leaf revisions of a document, or all of them if revs is empty, including the
+// conflicting ones and the deleted ones. Unlike GetDoc, which only returns the winning revision, it gives
+// conflict-resolution tooling every version of the document to choose from.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - id: The ID of the document.
+//   - revs: The revisions to retrieve; if empty, all the leaf revisions are retrieved.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - []OpenRev: The revisions, in no particular order. Requested revisions that don't exist are returned with Missing set.
+//   - error: ErrNotFound if the document doesn't exist, or any other error encountered.
+//
+// Example:
+//
+//	leaves, err := db.GetOpenRevs(ctx, "invoice:42", nil)
+//	if err != nil {
+//	    log.Fatalf("Error getting revisions: %v", err)
+//	}
+//	for _, leaf := range leaves {
+//	    var invoice Invoice
+//	    if err := json.Unmarshal(leaf.Ok, &invoice); err == nil {
+//	        fmt.Println(invoice.Rev, invoice.Total)
+//	    }
+//	}
+func (db *Database) GetOpenRevs(ctx context.Context, id string, revs []string, opts ...RequestOption) ([]OpenRev, error) {
+	values := url.Values{}
+	if len(revs) == 0 {
+		values.Set("open_revs", "all")
+	} else {
+		openRevs, err := json.Marshal(revs)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding open revs: %w", err)
+		}
+		values.Set("open_revs", string(openRevs))
+	}
+
+	// Without an explicit Accept header, CouchDB answers with a multipart/mixed response.
+	respCode, respBody, err := db.httpClient.Get(ctx, withQuery(docPath(db.dbName, id), values), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting open revs: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting open revs", respCode, respBody)
+	}
+
+	var openRevs []OpenRev
+	err = json.Unmarshal(respBody, &openRevs)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling open revs: %w", err)
+	}
+
+	return openRevs, nil
+}
@@ -0,0 +1,200 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRevsLimit(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test/_revs_limit" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			received = string(body)
+			w.Write([]byte(`{"ok":true}`))
+		case http.MethodGet:
+			w.Write([]byte("1000\n"))
+		}
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	limit, err := db.GetRevsLimit(context.Background())
+	if err != nil || limit != 1000 {
+		t.Errorf("Expected limit 1000, got %d, %v", limit, err)
+	}
+	if err := db.SetRevsLimit(context.Background(), 100); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if received != "100" {
+		t.Errorf("Expected body 100, got %q", received)
+	}
+}
+
+func TestRevsLimitErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"unauthorized","reason":"You are not a db or server admin."}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	if _, err := db.GetRevsLimit(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if err := db.SetRevsLimit(context.Background(), 100); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestMissingRevsAndRevsDiff(t *testing.T) {
+	// The server knows 1-a and 2-b of doc1, and nothing of doc2.
+	var received map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Error decoding request body: %v", err)
+		}
+		switch r.URL.Path {
+		case "/test/_missing_revs":
+			w.Write([]byte(`{"missing_revs":{"doc1":["3-c"],"doc2":["1-z"]}}`))
+		case "/test/_revs_diff":
+			w.Write([]byte(`{"doc1":{"missing":["3-c"],"possible_ancestors":["2-b"]},"doc2":{"missing":["1-z"]}}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+	revs := map[string][]string{"doc1": {"2-b", "3-c"}, "doc2": {"1-z"}}
+
+	missing, err := db.MissingRevs(context.Background(), revs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(received, revs) {
+		t.Errorf("Expected request %v, got %v", revs, received)
+	}
+	expectedMissing := map[string][]string{"doc1": {"3-c"}, "doc2": {"1-z"}}
+	if !reflect.DeepEqual(missing, expectedMissing) {
+		t.Errorf("Expected %v, got %v", expectedMissing, missing)
+	}
+
+	diff, err := db.RevsDiff(context.Background(), revs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedDiff := map[string]RevsDiff{
+		"doc1": {Missing: []string{"3-c"}, PossibleAncestors: []string{"2-b"}},
+		"doc2": {Missing: []string{"1-z"}},
+	}
+	if !reflect.DeepEqual(diff, expectedDiff) {
+		t.Errorf("Expected %+v, got %+v", expectedDiff, diff)
+	}
+}
+
+func TestGetOpenRevs(t *testing.T) {
+	var received *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		if r.URL.Path == "/test/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			return
+		}
+		if r.URL.Query().Get("open_revs") == "all" {
+			w.Write([]byte(`[{"ok":{"_id":"doc1","_rev":"2-b","v":1}},{"ok":{"_id":"doc1","_rev":"2-c","v":2}}]`))
+			return
+		}
+		w.Write([]byte(`[{"ok":{"_id":"doc1","_rev":"2-b","v":1}},{"missing":"3-x"}]`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name             string
+		id               string
+		revs             []string
+		expectedOpenRevs string
+		expectedRevs     []string
+		expectedMissing  []string
+		err              error
+	}{
+		{name: "all leaves", id: "doc1", expectedOpenRevs: "all", expectedRevs: []string{"2-b", "2-c"}},
+		{name: "explicit revs", id: "doc1", revs: []string{"2-b", "3-x"}, expectedOpenRevs: `["2-b","3-x"]`, expectedRevs: []string{"2-b"}, expectedMissing: []string{"3-x"}},
+		{name: "missing document", id: "missing", expectedOpenRevs: "all", err: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			openRevs, err := db.GetOpenRevs(context.Background(), tc.id, tc.revs)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if got := received.URL.Query().Get("open_revs"); got != tc.expectedOpenRevs {
+				t.Errorf("Expected open_revs %q, got %q", tc.expectedOpenRevs, got)
+			}
+			if accept := received.Header.Get("Accept"); accept != "application/json" {
+				t.Errorf("Expected Accept application/json, got %q", accept)
+			}
+
+			var revs, missing []string
+			for _, openRev := range openRevs {
+				if openRev.Missing != "" {
+					missing = append(missing, openRev.Missing)
+					continue
+				}
+				var doc Document
+				if err := json.Unmarshal(openRev.Ok, &doc); err != nil {
+					t.Fatalf("Error unmarshalling revision: %v", err)
+				}
+				revs = append(revs, doc.Rev)
+			}
+			if !reflect.DeepEqual(revs, tc.expectedRevs) || !reflect.DeepEqual(missing, tc.expectedMissing) {
+				t.Errorf("Expected revs %v and missing %v, got %v and %v", tc.expectedRevs, tc.expectedMissing, revs, missing)
+			}
+		})
+	}
+}
+
+func TestGetDocRevisionHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"_id":"doc1","_rev":"3-c",
+			"_revisions":{"start":3,"ids":["c","b","a"]},
+			"_revs_info":[{"rev":"3-c","status":"available"},{"rev":"2-b","status":"deleted"},{"rev":"1-a","status":"missing"}]}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	var doc Document
+	if err := db.GetDocWithOptions(context.Background(), "doc1", GetDocOptions{Revs: true, RevsInfo: true}, &doc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedRevs := []string{"3-c", "2-b", "1-a"}
+	if doc.Revisions == nil || !reflect.DeepEqual(doc.Revisions.Revs(), expectedRevs) {
+		t.Errorf("Expected revisions %v, got %+v", expectedRevs, doc.Revisions)
+	}
+	expectedInfo := []RevInfo{{Rev: "3-c", Status: RevAvailable}, {Rev: "2-b", Status: RevDeleted}, {Rev: "1-a", Status: RevMissing}}
+	if !reflect.DeepEqual(doc.RevsInfo, expectedInfo) {
+		t.Errorf("Expected revs info %v, got %v", expectedInfo, doc.RevsInfo)
+	}
+}
@@ -0,0 +1,131 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// SchedulerJobEvent is an event in the history of a replication job, such as "started" or "crashed".
+type SchedulerJobEvent struct {
+	Timestamp string `json:"timestamp"`
+	Type      string `json:"type"`             // "added", "started", "crashed" or "stopped"
+	Reason    string `json:"reason,omitempty"` // Why the job crashed
+}
+
+// SchedulerJob is a replication job run by the scheduler, as returned by SchedulerJobs.
+type SchedulerJob struct {
+	ID        string              `json:"id"`       // Replication ID
+	Database  string              `json:"database"` // Replicator database of the job; empty if it was started with Replicate
+	DocID     string              `json:"doc_id"`   // ID of the replication document; empty if it was started with Replicate
+	Node      string              `json:"node"`
+	Pid       string              `json:"pid"`
+	Source    string              `json:"source"` // URL of the source, with credentials redacted
+	Target    string              `json:"target"` // URL of the target, with credentials redacted
+	User      string              `json:"user"`
+	StartTime string              `json:"start_time"`
+	History   []SchedulerJobEvent `json:"history"` // Events of the job, the most recent first
+}
+
+// SchedulerDocInfo holds the statistics of a replication, or why it isn't running.
+type SchedulerDocInfo struct {
+	ReplicationStats
+	Error string `json:"error,omitempty"` // Why the replication is in the error, crashing or failed state
+}
+
+// UnmarshalJSON decodes the info of a scheduler document, which some CouchDB versions report as a bare error message.
+func (i *SchedulerDocInfo) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*i = SchedulerDocInfo{}
+		return json.Unmarshal(data, &i.Error)
+	}
+	type info SchedulerDocInfo
+	return json.Unmarshal(data, (*info)(i))
+}
+
+// SchedulerDoc is the state of a replication document, as returned by SchedulerDocs.
+type SchedulerDoc struct {
+	Database    string            `json:"database"`
+	DocID       string            `json:"doc_id"`
+	ID          string            `json:"id"` // Replication ID; empty until the document has been processed
+	Node        string            `json:"node"`
+	Source      string            `json:"source"`
+	Target      string            `json:"target"`
+	State       string            `json:"state"`       // One of the Replication* states, such as ReplicationRunning
+	Info        *SchedulerDocInfo `json:"info"`        // Statistics of the replication, or why it isn't running
+	ErrorCount  int               `json:"error_count"` // Number of consecutive errors, which determines how long the scheduler backs off
+	LastUpdated string            `json:"last_updated"`
+	StartTime   string            `json:"start_time"`
+}
+
+// SchedulerJobs returns the replication jobs currently run by the scheduler, whether they were started with
+// Replicate or from a replication document. Completed and failed replications have no job.
+func (c *CouchService) SchedulerJobs(ctx context.Context) ([]SchedulerJob, error) {
+	respCode, respBody, err := c.httpClient.Get(ctx, "_scheduler/jobs")
+	if err != nil {
+		return nil, fmt.Errorf("error getting scheduler jobs: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting scheduler jobs", respCode, respBody)
+	}
+
+	var jobsResponse struct {
+		Jobs []SchedulerJob `json:"jobs"`
+	}
+	err = json.Unmarshal(respBody, &jobsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling scheduler jobs: %w", err)
+	}
+
+	return jobsResponse.Jobs, nil
+}
+
+// SchedulerDocs returns the state of every document of a replicator database, including why a replication
+// is crashing and how many times in a row it failed, which replication documents only record once failed.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - replicatorDB: The name of the replicator database, e.g. "tenant42/_replicator"; empty means _replicator.
+//
+// Returns:
+//   - []SchedulerDoc: The state of each replication document.
+//   - error: An error, if any, encountered while getting the states.
+//
+// Example:
+//
+//	docs, err := cs.SchedulerDocs(ctx, "")
+//	if err != nil {
+//	    log.Fatalf("Error getting replication states: %v", err)
+//	}
+//	for _, doc := range docs {
+//	    if doc.State == couchdb.ReplicationCrashing {
+//	        log.Printf("Replication %s crashed %d times: %s", doc.DocID, doc.ErrorCount, doc.Info.Error)
+//	    }
+//	}
+func (c *CouchService) SchedulerDocs(ctx context.Context, replicatorDB string) ([]SchedulerDoc, error) {
+	endpoint := "_scheduler/docs"
+	if replicatorDB != "" {
+		endpoint = fmt.Sprintf("%s/%s", endpoint, url.PathEscape(replicatorDB))
+	}
+
+	respCode, respBody, err := c.httpClient.Get(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scheduler docs: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting scheduler docs", respCode, respBody)
+	}
+
+	var docsResponse struct {
+		Docs []SchedulerDoc `json:"docs"`
+	}
+	err = json.Unmarshal(respBody, &docsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling scheduler docs: %w", err)
+	}
+
+	return docsResponse.Docs, nil
+}
@@ -0,0 +1,106 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSchedulerJobs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_scheduler/jobs" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"total_rows":1,"offset":0,"jobs":[{
+			"id":"a81a+continuous","database":"_replicator","doc_id":"orders-backup","node":"node1@127.0.0.1",
+			"source":"http://localhost:5984/orders/","target":"http://backup:5984/orders/","user":null,
+			"history":[{"timestamp":"2024-05-01T10:00:05Z","type":"crashed","reason":"db_not_found"},{"timestamp":"2024-05-01T10:00:00Z","type":"started"}]
+		}]}`))
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	jobs, err := cs.SchedulerJobs(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].DocID != "orders-backup" || len(jobs[0].History) != 2 || jobs[0].History[0].Reason != "db_not_found" {
+		t.Errorf("Unexpected jobs %+v", jobs)
+	}
+}
+
+func TestSchedulerDocs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.RawPath {
+		case "", "/_scheduler/docs":
+			if r.URL.Path != "/_scheduler/docs" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+				return
+			}
+			w.Write([]byte(`{"total_rows":2,"offset":0,"docs":[
+				{"database":"_replicator","doc_id":"orders-backup","id":"a81a+continuous","state":"crashing",
+				 "info":{"error":"db_not_found: could not open http://backup:5984/orders/"},"error_count":3},
+				{"database":"_replicator","doc_id":"users-backup","id":"b72b","state":"running",
+				 "info":{"docs_read":10,"docs_written":9,"doc_write_failures":1,"changes_pending":4},"error_count":0}
+			]}`))
+		case "/_scheduler/docs/tenant42%2F_replicator":
+			w.Write([]byte(`{"total_rows":1,"offset":0,"docs":[
+				{"database":"tenant42/_replicator","doc_id":"invalid","id":null,"state":"failed","info":"Replication document has no source","error_count":1}
+			]}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.RawPath)
+		}
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	testCases := []struct {
+		name         string
+		replicatorDB string
+		check        func(t *testing.T, docs []SchedulerDoc)
+	}{
+		{
+			name: "default replicator database",
+			check: func(t *testing.T, docs []SchedulerDoc) {
+				if len(docs) != 2 {
+					t.Fatalf("Expected 2 docs, got %d", len(docs))
+				}
+				if docs[0].State != ReplicationCrashing || docs[0].ErrorCount != 3 || docs[0].Info.Error == "" {
+					t.Errorf("Unexpected crashing doc %+v", docs[0])
+				}
+				if docs[1].State != ReplicationRunning || docs[1].Info.DocsWritten != 9 || docs[1].Info.ChangesPending != 4 {
+					t.Errorf("Unexpected running doc %+v", docs[1])
+				}
+			},
+		},
+		{
+			name:         "other replicator database",
+			replicatorDB: "tenant42/_replicator",
+			check: func(t *testing.T, docs []SchedulerDoc) {
+				if len(docs) != 1 || docs[0].State != ReplicationFailed || docs[0].Info.Error != "Replication document has no source" {
+					t.Errorf("Unexpected docs %+v", docs)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			docs, err := cs.SchedulerDocs(context.Background(), tc.replicatorDB)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tc.check(t, docs)
+		})
+	}
+
+	if _, err := cs.SchedulerDocs(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
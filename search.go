@@ -0,0 +1,97 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// SearchIndex defines a full-text search index in the SearchIndexes field of a design document.
+// Search indexes are served by Clouseau, which must be installed alongside CouchDB.
+type SearchIndex struct {
+	// Index is the JavaScript function called for each document, which indexes its fields with index(name, value, options).
+	Index string `json:"index"`
+	// Analyzer is the name of the Lucene analyzer, e.g. "standard" (the default) or "english",
+	// or an object configuring a "perfield" analyzer.
+	Analyzer any `json:"analyzer,omitempty"`
+}
+
+// SearchQuery are the parameters of a search query, as described [here](https://docs.couchdb.org/en/stable/api/ddoc/search.html).
+type SearchQuery struct {
+	Query         string                       `json:"q"`                        // Lucene query, e.g. "title:couch* AND year:[2010 TO 2020]"
+	Sort          []string                     `json:"sort,omitempty"`           // Fields to sort by, prefixed with "-" for descending order, e.g. "-year<number>"
+	Limit         int                          `json:"limit,omitempty"`          // Maximum number of rows to return; the server default is 25
+	Bookmark      string                       `json:"bookmark,omitempty"`       // Bookmark returned by a previous query, for pagination
+	IncludeDocs   bool                         `json:"include_docs,omitempty"`   // Include the matching document in each row
+	IncludeFields []string                     `json:"include_fields,omitempty"` // Stored fields to return; all of them if empty
+	Counts        []string                     `json:"counts,omitempty"`         // Fields to count the distinct values of among the matches
+	Ranges        map[string]map[string]string `json:"ranges,omitempty"`         // Numeric fields to count the matches of by named range, e.g. {"price": {"cheap": "[0 TO 100]"}}
+	Drilldown     [][]string                   `json:"drilldown,omitempty"`      // [field, value...] pairs restricting the matches to the given values, e.g. from Counts
+	Stale         string                       `json:"stale,omitempty"`          // "ok" to answer from the index without updating it
+}
+
+// SearchRow is a document matching a search query.
+type SearchRow struct {
+	ID     string          `json:"id"`
+	Order  []any           `json:"order"`         // Sort values of the row, e.g. its relevance score
+	Fields map[string]any  `json:"fields"`        // Stored fields of the row
+	Doc    json.RawMessage `json:"doc,omitempty"` // Matching document; only set with SearchQuery.IncludeDocs
+}
+
+// SearchResponse is the response of a search query. It can be used as the resultVar of Search.
+type SearchResponse struct {
+	TotalRows int                       `json:"total_rows"`       // Number of documents matching the query, regardless of Limit
+	Bookmark  string                    `json:"bookmark"`         // Bookmark to pass in the next query to get the following rows
+	Rows      []SearchRow               `json:"rows"`             // Matching documents
+	Counts    map[string]map[string]int `json:"counts,omitempty"` // Number of matches by value of each field in SearchQuery.Counts
+	Ranges    map[string]map[string]int `json:"ranges,omitempty"` // Number of matches by range of each field in SearchQuery.Ranges
+}
+
+// Search runs a full-text query against a search index defined in the SearchIndexes field of a design document.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - design: The name of the design document, with or without the "_design/" prefix.
+//   - index: The name of the search index.
+//   - query: The parameters of the query.
+//   - resultVar: A pointer to a struct (such as SearchResponse) or map where the response will be unmarshalled.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - error: An error, if any, encountered while running the query.
+//     ErrBadRequest is returned if the query is not valid Lucene syntax.
+//
+// Example:
+//
+//	var result couchdb.SearchResponse
+//	err := db.Search(ctx, "books", "by_text", couchdb.SearchQuery{
+//	    Query:  "title:couch*",
+//	    Counts: []string{"genre"},
+//	    Limit:  10,
+//	}, &result)
+//	if err != nil {
+//	    log.Fatalf("Error searching books: %v", err)
+//	}
+//	fmt.Println(result.TotalRows, result.Counts["genre"])
+func (db *Database) Search(ctx context.Context, design, index string, query SearchQuery, resultVar any, opts ...RequestOption) error {
+	if !isValidParam(resultVar) {
+		return fmt.Errorf("resultVar parameter must be a pointer to a struct")
+	}
+
+	endpoint := designPath(db.dbName, design, "_search", index)
+	respCode, respBody, err := db.httpClient.Post(ctx, endpoint, query, opts...)
+	if err != nil {
+		return fmt.Errorf("error running search query: %w", err)
+	}
+
+	if respCode != 200 {
+		return responseError("error running search query", respCode, respBody)
+	}
+
+	err = json.Unmarshal(respBody, resultVar)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling into resultVar: %w", err)
+	}
+
+	return nil
+}
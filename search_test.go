@@ -0,0 +1,94 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSearch(t *testing.T) {
+	var received map[string]any
+	var receivedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		received = nil
+		json.NewDecoder(r.Body).Decode(&received)
+		if received["q"] == "title:[" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"text_search_error","reason":"Cannot parse 'title:['"}`))
+			return
+		}
+		w.Write([]byte(`{"total_rows":2,"bookmark":"g1AAAA","rows":[
+			{"id":"book1","order":[1.5,0],"fields":{"title":"CouchDB"}},
+			{"id":"book2","order":[0.8,1],"fields":{"title":"Couch potato"}}
+		],"counts":{"genre":{"tech":1,"fiction":1}}}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name         string
+		query        SearchQuery
+		expectedBody map[string]any
+		err          error
+	}{
+		{
+			name:         "query only",
+			query:        SearchQuery{Query: "title:couch*"},
+			expectedBody: map[string]any{"q": "title:couch*"},
+		},
+		{
+			name: "all parameters",
+			query: SearchQuery{
+				Query:     "title:couch*",
+				Sort:      []string{"-year<number>"},
+				Limit:     10,
+				Bookmark:  "g1AAAA",
+				Counts:    []string{"genre"},
+				Drilldown: [][]string{{"genre", "tech"}},
+			},
+			expectedBody: map[string]any{
+				"q":         "title:couch*",
+				"sort":      []any{"-year<number>"},
+				"limit":     float64(10),
+				"bookmark":  "g1AAAA",
+				"counts":    []any{"genre"},
+				"drilldown": []any{[]any{"genre", "tech"}},
+			},
+		},
+		{
+			name:         "invalid query",
+			query:        SearchQuery{Query: "title:["},
+			expectedBody: map[string]any{"q": "title:["},
+			err:          ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var result SearchResponse
+			err := db.Search(context.Background(), "_design/books", "by_text", tc.query, &result)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if receivedPath != "/test/_design/books/_search/by_text" {
+				t.Errorf("Unexpected path %s", receivedPath)
+			}
+			if !reflect.DeepEqual(received, tc.expectedBody) {
+				t.Errorf("Expected body %v, got %v", tc.expectedBody, received)
+			}
+			if tc.err != nil {
+				return
+			}
+			if result.TotalRows != 2 || len(result.Rows) != 2 || result.Rows[0].Fields["title"] != "CouchDB" || result.Counts["genre"]["tech"] != 1 {
+				t.Errorf("Unexpected result %+v", result)
+			}
+		})
+	}
+}
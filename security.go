@@ -0,0 +1,73 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// SecurityObject is the security object of a database, defining who can administer and read it.
+// A database whose members are empty is readable by any user, so per-tenant databases should always list members.
+type SecurityObject struct {
+	Admins  SecurityGroup `json:"admins"`  // Users and roles allowed to change design documents and the security object
+	Members SecurityGroup `json:"members"` // Users and roles allowed to read and write documents
+}
+
+// SecurityGroup is a set of users and roles in a SecurityObject.
+type SecurityGroup struct {
+	Names []string `json:"names,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// GetSecurity returns the security object of the database.
+func (db *Database) GetSecurity(ctx context.Context, opts ...RequestOption) (*SecurityObject, error) {
+	respCode, respBody, err := db.httpClient.Get(ctx, fmt.Sprintf("%s/_security", db.dbName), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting security object: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting security object", respCode, respBody)
+	}
+
+	var security SecurityObject
+	err = json.Unmarshal(respBody, &security)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling security object: %w", err)
+	}
+
+	return &security, nil
+}
+
+// SetSecurity replaces the security object of the database. It requires server or database admin privileges.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - security: The new security object.
+//   - opts: Optional per-request options, e.g. WithHeader or WithRequestTimeout.
+//
+// Returns:
+//   - An error, if any, encountered while setting the security object.
+//     If the update is successful, it returns nil.
+//
+// Example:
+//
+//	err := db.SetSecurity(ctx, couchdb.SecurityObject{
+//	    Admins:  couchdb.SecurityGroup{Roles: []string{"tenant-42-admin"}},
+//	    Members: couchdb.SecurityGroup{Roles: []string{"tenant-42"}},
+//	})
+//	if err != nil {
+//	    log.Fatalf("Error setting security object: %v", err)
+//	}
+func (db *Database) SetSecurity(ctx context.Context, security SecurityObject, opts ...RequestOption) error {
+	respCode, respBody, err := db.httpClient.Put(ctx, fmt.Sprintf("%s/_security", db.dbName), security, opts...)
+	if err != nil {
+		return fmt.Errorf("error setting security object: %w", err)
+	}
+
+	if respCode != 200 {
+		return responseError("error setting security object", respCode, respBody)
+	}
+
+	return nil
+}
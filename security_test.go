@@ -0,0 +1,128 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newSecurityServer returns a server that stores the security object of the test database, starting from initial.
+func newSecurityServer(t *testing.T, initial string) *httptest.Server {
+	var mu sync.Mutex
+	stored := initial
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test/_security" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(stored))
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			if !json.Valid(body) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"bad_request","reason":"invalid_json"}`))
+				return
+			}
+			stored = string(body)
+			w.Write([]byte(`{"ok":true}`))
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	}))
+}
+
+func TestGetSecurity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stored   string
+		expected SecurityObject
+	}{
+		{name: "empty security object", stored: `{}`, expected: SecurityObject{}},
+		{
+			name:   "admins and members",
+			stored: `{"admins":{"names":["ops"],"roles":["_admin"]},"members":{"roles":["tenant-42"]}}`,
+			expected: SecurityObject{
+				Admins:  SecurityGroup{Names: []string{"ops"}, Roles: []string{"_admin"}},
+				Members: SecurityGroup{Roles: []string{"tenant-42"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newSecurityServer(t, tc.stored)
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			security, err := db.GetSecurity(context.Background())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*security, tc.expected) {
+				t.Errorf("Expected %+v, got %+v", tc.expected, *security)
+			}
+		})
+	}
+}
+
+func TestSecurityRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name     string
+		security SecurityObject
+	}{
+		{name: "empty security object", security: SecurityObject{}},
+		{
+			name: "admins and members",
+			security: SecurityObject{
+				Admins:  SecurityGroup{Roles: []string{"tenant-42-admin"}},
+				Members: SecurityGroup{Names: []string{"jane"}, Roles: []string{"tenant-42"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newSecurityServer(t, `{"members":{"roles":["previous"]}}`)
+			defer server.Close()
+
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+			if err := db.SetSecurity(context.Background(), tc.security); err != nil {
+				t.Fatalf("Unexpected error setting security object: %v", err)
+			}
+			security, err := db.GetSecurity(context.Background())
+			if err != nil {
+				t.Fatalf("Unexpected error getting security object: %v", err)
+			}
+			if !reflect.DeepEqual(*security, tc.security) {
+				t.Errorf("Expected %+v, got %+v", tc.security, *security)
+			}
+		})
+	}
+}
+
+func TestSecurityErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":"forbidden","reason":"You are not a db or server admin."}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+	if _, err := db.GetSecurity(context.Background()); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden getting the security object, got %v", err)
+	}
+	if err := db.SetSecurity(context.Background(), SecurityObject{}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden setting the security object, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+// ServerVendor identifies the distributor of a CouchDB server.
+type ServerVendor struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// ServerInfo is the information a CouchDB server reports about itself at its root endpoint.
+type ServerInfo struct {
+	CouchDB  string       `json:"couchdb"` // Always "Welcome"
+	Version  string       `json:"version"` // CouchDB version, e.g. "3.3.3"
+	GitSHA   string       `json:"git_sha"`
+	UUID     string       `json:"uuid"`     // Identifier of the server
+	Features []string     `json:"features"` // Optional features enabled on the server, e.g. "partitioned" or "nouveau"
+	Vendor   ServerVendor `json:"vendor"`
+}
+
+// HasFeature reports whether the server has the given optional feature enabled, e.g. to check that
+// partitioned databases or Nouveau are available before relying on them.
+func (info *ServerInfo) HasFeature(feature string) bool {
+	return slices.Contains(info.Features, feature)
+}
+
+// ServerInfo returns the version, enabled features and vendor of the server.
+//
+// Example:
+//
+//	info, err := cs.ServerInfo(ctx)
+//	if err != nil {
+//	    log.Fatalf("Error getting server info: %v", err)
+//	}
+//	if !info.HasFeature("nouveau") {
+//	    log.Fatalf("CouchDB %s doesn't have Nouveau enabled", info.Version)
+//	}
+func (c *CouchService) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	respCode, respBody, err := c.httpClient.Get(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("error getting server info: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting server info", respCode, respBody)
+	}
+
+	var info ServerInfo
+	err = json.Unmarshal(respBody, &info)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling server info: %w", err)
+	}
+
+	return &info, nil
+}
+
+// Up checks whether the node is ready to serve requests, for use in readiness probes. Unlike other requests,
+// the check is attempted only once, so that a node which is down is reported right away.
+//
+// Returns:
+//   - error: nil if the node is up; an error wrapping ErrServerError if it is down or in maintenance mode,
+//     or any other error encountered while reaching it.
+//
+// Example:
+//
+//	http.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+//	    if err := cs.Up(r.Context()); err != nil {
+//	        http.Error(w, err.Error(), http.StatusServiceUnavailable)
+//	    }
+//	})
+func (c *CouchService) Up(ctx context.Context) error {
+	resp, err := c.httpClient.do(ctx, &request{method: "GET", endpoint: "_up", noRetry: true})
+	if err != nil {
+		return fmt.Errorf("error checking server status: %w", err)
+	}
+
+	if resp.statusCode != 200 {
+		return responseError("error checking server status", resp.statusCode, resp.body)
+	}
+
+	return nil
+}
+
+// UUIDs returns count universally unique identifiers generated by the server, e.g. to assign the IDs of
+// documents before inserting them in bulk. By default, the server generates at most 1000 UUIDs per request.
+//
+// Example:
+//
+//	ids, err := cs.UUIDs(ctx, len(orders))
+//	if err != nil {
+//	    log.Fatalf("Error generating IDs: %v", err)
+//	}
+//	for i := range orders {
+//	    orders[i].ID = ids[i]
+//	}
+func (c *CouchService) UUIDs(ctx context.Context, count int) ([]string, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("invalid uuid count %d: must be positive", count)
+	}
+
+	values := url.Values{}
+	values.Set("count", strconv.Itoa(count))
+
+	respCode, respBody, err := c.httpClient.Get(ctx, withQuery("_uuids", values))
+	if err != nil {
+		return nil, fmt.Errorf("error getting uuids: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting uuids", respCode, respBody)
+	}
+
+	var uuidsResponse struct {
+		UUIDs []string `json:"uuids"`
+	}
+	err = json.Unmarshal(respBody, &uuidsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling uuids: %w", err)
+	}
+
+	return uuidsResponse.UUIDs, nil
+}
+
+// Membership lists the nodes of a cluster, as returned by Membership.
+type Membership struct {
+	AllNodes     []string `json:"all_nodes"`     // Nodes this node is connected to, including itself
+	ClusterNodes []string `json:"cluster_nodes"` // Nodes configured as members of the cluster
+}
+
+// Membership returns the nodes of the cluster. A node listed in ClusterNodes but missing from AllNodes
+// is a member the queried node can't reach.
+//
+// Example:
+//
+//	membership, err := cs.Membership(ctx)
+//	if err != nil {
+//	    log.Fatalf("Error getting cluster membership: %v", err)
+//	}
+//	for _, node := range membership.ClusterNodes {
+//	    if !slices.Contains(membership.AllNodes, node) {
+//	        log.Printf("Node %s is unreachable", node)
+//	    }
+//	}
+func (c *CouchService) Membership(ctx context.Context) (*Membership, error) {
+	respCode, respBody, err := c.httpClient.Get(ctx, "_membership")
+	if err != nil {
+		return nil, fmt.Errorf("error getting membership: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting membership", respCode, respBody)
+	}
+
+	var membership Membership
+	err = json.Unmarshal(respBody, &membership)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling membership: %w", err)
+	}
+
+	return &membership, nil
+}
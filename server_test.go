@@ -0,0 +1,151 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServerInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"couchdb":"Welcome","version":"3.4.1","git_sha":"b3b7f1d","uuid":"85fb71bf700c17267fef77535820e371",
+			"features":["access-ready","partitioned","pluggable-storage-engines","reshard","scheduler","nouveau"],
+			"vendor":{"name":"The Apache Software Foundation"}}`))
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	info, err := cs.ServerInfo(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.Version != "3.4.1" || info.Vendor.Name != "The Apache Software Foundation" || info.UUID == "" {
+		t.Errorf("Unexpected server info %+v", info)
+	}
+
+	testCases := []struct {
+		feature  string
+		expected bool
+	}{
+		{feature: "nouveau", expected: true},
+		{feature: "partitioned", expected: true},
+		{feature: "quickjs", expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.feature, func(t *testing.T) {
+			if got := info.HasFeature(tc.feature); got != tc.expected {
+				t.Errorf("Expected HasFeature(%q) to be %v, got %v", tc.feature, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestUp(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status int
+		body   string
+		err    error
+	}{
+		{name: "up", status: http.StatusOK, body: `{"status":"ok","seeds":{}}`},
+		{name: "maintenance mode", status: http.StatusServiceUnavailable, body: `{"status":"maintenance_mode"}`, err: ErrServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.URL.Path != "/_up" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 3, time.Millisecond, time.Second)}
+
+			if err := cs.Up(context.Background()); !errors.Is(err, tc.err) {
+				t.Errorf("Expected error %v, got %v", tc.err, err)
+			}
+			if requests != 1 {
+				t.Errorf("Expected a single attempt, got %d", requests)
+			}
+		})
+	}
+}
+
+func TestUUIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_uuids" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("count") == "2000" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad_request","reason":"count parameter too large"}`))
+			return
+		}
+		w.Write([]byte(`{"uuids":["75480ca477454894678e22eec6002413","75480ca477454894678e22eec600250b"]}`))
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	testCases := []struct {
+		name          string
+		count         int
+		expectedCount int
+		expectedError bool
+		err           error
+	}{
+		{name: "valid count", count: 2, expectedCount: 2},
+		{name: "zero count", count: 0, expectedError: true},
+		{name: "count above server limit", count: 2000, expectedError: true, err: ErrBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uuids, err := cs.UUIDs(context.Background(), tc.count)
+			if tc.expectedError {
+				if err == nil || (tc.err != nil && !errors.Is(err, tc.err)) {
+					t.Errorf("Expected error %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(uuids) != tc.expectedCount {
+				t.Errorf("Expected %d uuids, got %d", tc.expectedCount, len(uuids))
+			}
+		})
+	}
+}
+
+func TestMembership(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_membership" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"all_nodes":["node1@10.0.0.1","node2@10.0.0.2"],"cluster_nodes":["node1@10.0.0.1","node2@10.0.0.2","node3@10.0.0.3"]}`))
+	}))
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+
+	membership, err := cs.Membership(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(membership.AllNodes) != 2 || len(membership.ClusterNodes) != 3 || membership.ClusterNodes[2] != "node3@10.0.0.3" {
+		t.Errorf("Unexpected membership %+v", membership)
+	}
+}
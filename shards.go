@@ -0,0 +1,75 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// DocShard is the shard a document is stored in, as returned by ShardForDoc.
+type DocShard struct {
+	Range string   `json:"range"` // Range of the hashes of the IDs in the shard, e.g. "00000000-7fffffff"
+	Nodes []string `json:"nodes"` // Nodes holding a copy of the shard
+}
+
+// Shards returns the layout of the shards of the database, i.e. the nodes holding a copy of each shard,
+// by the range of the shard, e.g. "00000000-7fffffff".
+//
+// Example:
+//
+//	shards, err := db.Shards(ctx)
+//	if err != nil {
+//	    log.Fatalf("Error getting shards: %v", err)
+//	}
+//	for shardRange, nodes := range shards {
+//	    fmt.Printf("%s: %s\n", shardRange, strings.Join(nodes, ", "))
+//	}
+func (db *Database) Shards(ctx context.Context, opts ...RequestOption) (map[string][]string, error) {
+	respCode, respBody, err := db.httpClient.Get(ctx, fmt.Sprintf("%s/_shards", db.dbName), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting shards: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting shards", respCode, respBody)
+	}
+
+	var shardsResponse struct {
+		Shards map[string][]string `json:"shards"`
+	}
+	err = json.Unmarshal(respBody, &shardsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling shards: %w", err)
+	}
+
+	return shardsResponse.Shards, nil
+}
+
+// ShardForDoc returns the shard the document with the given ID is stored in, or would be if it existed,
+// e.g. to find out whether the documents of a hot spot all land in the same shard.
+//
+// Example:
+//
+//	shard, err := db.ShardForDoc(ctx, "order:1234")
+//	if err != nil {
+//	    log.Fatalf("Error getting shard: %v", err)
+//	}
+//	fmt.Printf("Stored in %s on %s\n", shard.Range, strings.Join(shard.Nodes, ", "))
+func (db *Database) ShardForDoc(ctx context.Context, docID string, opts ...RequestOption) (*DocShard, error) {
+	respCode, respBody, err := db.httpClient.Get(ctx, fmt.Sprintf("%s/_shards/%s", db.dbName, escapeDocID(docID)), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting document shard: %w", err)
+	}
+
+	if respCode != 200 {
+		return nil, responseError("error getting document shard", respCode, respBody)
+	}
+
+	var shard DocShard
+	err = json.Unmarshal(respBody, &shard)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling document shard: %w", err)
+	}
+
+	return &shard, nil
+}
@@ -0,0 +1,71 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShards(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test/_shards" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not_found","reason":"Database does not exist."}`))
+			return
+		}
+		w.Write([]byte(`{"shards":{"00000000-7fffffff":["couchdb@10.0.0.1","couchdb@10.0.0.2"],"80000000-ffffffff":["couchdb@10.0.0.2","couchdb@10.0.0.3"]}}`))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name   string
+		dbName string
+		shards int
+		err    error
+	}{
+		{name: "existing database", dbName: "test", shards: 2},
+		{name: "missing database", dbName: "missing", err: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: tc.dbName}
+			shards, err := db.Shards(context.Background())
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if len(shards) != tc.shards {
+				t.Fatalf("Expected %d shards, got %d", tc.shards, len(shards))
+			}
+			if tc.shards > 0 && strings.Join(shards["80000000-ffffffff"], ",") != "couchdb@10.0.0.2,couchdb@10.0.0.3" {
+				t.Errorf("Unexpected nodes %v", shards["80000000-ffffffff"])
+			}
+		})
+	}
+}
+
+func TestShardForDoc(t *testing.T) {
+	var receivedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.Write([]byte(`{"range":"80000000-ffffffff","nodes":["couchdb@10.0.0.2","couchdb@10.0.0.3"]}`))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	shard, err := db.ShardForDoc(context.Background(), "order-1234")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if receivedPath != "/test/_shards/order-1234" {
+		t.Errorf("Expected path /test/_shards/order-1234, got %s", receivedPath)
+	}
+	if shard.Range != "80000000-ffffffff" || len(shard.Nodes) != 2 {
+		t.Errorf("Unexpected shard %+v", shard)
+	}
+}
@@ -0,0 +1,215 @@
+package couchdb
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithTLSConfig(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // The untrusted case makes the handshake fail
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	cfg := &tls.Config{RootCAs: pool}
+
+	testCases := []struct {
+		name      string
+		opts      []Option
+		expectErr bool
+	}{
+		{name: "untrusted certificate", opts: nil, expectErr: true},
+		{name: "trusted private CA", opts: []Option{WithTLSConfig(cfg)}, expectErr: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)
+			for _, opt := range tc.opts {
+				opt(client)
+			}
+
+			_, _, err := client.Get(context.Background(), "")
+			if (err != nil) != tc.expectErr {
+				t.Errorf("Expected error: %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+
+	if http.DefaultTransport.(*http.Transport).TLSClientConfig == cfg {
+		t.Errorf("Expected the default transport to be left untouched")
+	}
+}
+
+// roundTripperFunc is a test double implementing http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	var requested string
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requested = req.URL.String()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+		}, nil
+	})
+
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "round tripper", opts: []Option{WithTransport(rt)}},
+		{name: "http client", opts: []Option{WithHTTPClient(&http.Client{Transport: rt})}},
+		{name: "transport options are ignored by custom round trippers", opts: []Option{WithTransport(rt), WithTLSConfig(&tls.Config{})}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requested = ""
+			client := NewCustomHTTPClient("http://couch.invalid/", 1, time.Millisecond, time.Second, tc.opts...)
+
+			code, _, err := client.Get(context.Background(), "test")
+			if err != nil || code != http.StatusOK {
+				t.Fatalf("Unexpected response: %d, %v", code, err)
+			}
+			if requested != "http://couch.invalid/test" {
+				t.Errorf("Expected the request to go through the custom transport, got %q", requested)
+			}
+		})
+	}
+}
+
+func TestWithUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "couch.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("Unix sockets not available: %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	client := NewCustomHTTPClient("http://localhost/", 1, time.Millisecond, time.Second, WithUnixSocket(socket))
+
+	code, _, err := client.Get(context.Background(), "test")
+	if err != nil || code != http.StatusOK {
+		t.Errorf("Unexpected response: %d, %v", code, err)
+	}
+}
+
+func TestWithHTTPProxy(t *testing.T) {
+	var proxied, proxyAuth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxyURL.User = url.UserPassword("jane", "s3cret")
+
+	client := NewCustomHTTPClient("http://couch.invalid/", 1, time.Millisecond, time.Second, WithHTTPProxy(proxyURL))
+
+	code, _, err := client.Get(context.Background(), "test")
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("Unexpected response: %d, %v", code, err)
+	}
+	if proxied != "http://couch.invalid/test" {
+		t.Errorf("Expected the request to go through the proxy, got %q", proxied)
+	}
+	if proxyAuth != "Basic amFuZTpzM2NyZXQ=" {
+		t.Errorf("Expected proxy credentials to be sent, got %q", proxyAuth)
+	}
+}
+
+func TestWithConnectionPool(t *testing.T) {
+	client := NewCustomHTTPClient("http://localhost/", 1, time.Millisecond, time.Second,
+		WithConnectionPool(PoolConfig{MaxIdleConnsPerHost: 50, MaxConnsPerHost: 64}))
+
+	transport := client.client.Transport.(*http.Transport)
+	defaults := http.DefaultTransport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != 50 || transport.MaxConnsPerHost != 64 {
+		t.Errorf("Expected the configured limits, got %d idle and %d total per host", transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost)
+	}
+	if transport.MaxIdleConns != defaults.MaxIdleConns || transport.IdleConnTimeout != defaults.IdleConnTimeout {
+		t.Errorf("Expected zero fields to keep the defaults")
+	}
+}
+
+func TestWithHTTPClientKeepsTransportUntouched(t *testing.T) {
+	cfg := &tls.Config{ServerName: "couch.internal"}
+	shared := &http.Transport{}
+
+	testCases := []struct {
+		name string
+		hc   *http.Client
+		opts func(hc *http.Client) []Option
+	}{
+		{
+			name: "transport options after WithHTTPClient",
+			hc:   &http.Client{Transport: shared},
+			opts: func(hc *http.Client) []Option { return []Option{WithHTTPClient(hc), WithTLSConfig(cfg)} },
+		},
+		{
+			name: "transport options before WithHTTPClient",
+			hc:   &http.Client{Transport: shared},
+			opts: func(hc *http.Client) []Option { return []Option{WithTLSConfig(cfg), WithHTTPClient(hc)} },
+		},
+		{
+			name: "client using the default transport",
+			hc:   &http.Client{Transport: http.DefaultTransport},
+			opts: func(hc *http.Client) []Option { return []Option{WithHTTPClient(hc), WithTLSConfig(cfg)} },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewCustomHTTPClient("http://localhost/", 1, time.Millisecond, time.Second, tc.opts(tc.hc)...)
+
+			transport, ok := client.client.Transport.(*http.Transport)
+			if !ok || transport.TLSClientConfig != cfg {
+				t.Errorf("Expected the TLS configuration to be applied to the client transport")
+			}
+			if transport == tc.hc.Transport {
+				t.Errorf("Expected the transport of the given client to be cloned")
+			}
+		})
+	}
+
+	// Cloning may set up HTTP/2 on the shared transport, but never applies the options to it.
+	if shared.TLSClientConfig == cfg {
+		t.Errorf("Expected the shared transport to be left untouched")
+	}
+	if http.DefaultTransport.(*http.Transport).TLSClientConfig == cfg {
+		t.Errorf("Expected the default transport to be left untouched")
+	}
+}
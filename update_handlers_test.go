@@ -0,0 +1,92 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUpdateHandler(t *testing.T) {
+	var received *http.Request
+	var receivedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ := io.ReadAll(r.Body)
+		receivedBody = string(body)
+		if r.URL.Path == "/test/_design/counters/_update/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not_found","reason":"missing function missing on design doc _design/counters"}`))
+			return
+		}
+		w.Header().Set("X-Couch-Id", "page:home")
+		w.Header().Set("X-Couch-Update-NewRev", "2-b")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("3"))
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name           string
+		design         string
+		handler        string
+		docID          string
+		body           any
+		expectedMethod string
+		expectedPath   string
+		expectedBody   string
+		err            error
+	}{
+		{
+			name:           "with document",
+			design:         "_design/counters",
+			handler:        "increment",
+			docID:          "page:home",
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/test/_design/counters/_update/increment/page:home",
+		},
+		{
+			name:           "without document",
+			design:         "counters",
+			handler:        "create",
+			body:           map[string]int{"count": 1},
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/test/_design/counters/_update/create",
+			expectedBody:   `{"count":1}`,
+		},
+		{
+			name:           "missing handler",
+			design:         "counters",
+			handler:        "missing",
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/test/_design/counters/_update/missing",
+			err:            ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := db.UpdateHandler(context.Background(), tc.design, tc.handler, tc.docID, tc.body)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if received.Method != tc.expectedMethod || received.URL.Path != tc.expectedPath {
+				t.Errorf("Expected %s %s, got %s %s", tc.expectedMethod, tc.expectedPath, received.Method, received.URL.Path)
+			}
+			if receivedBody != tc.expectedBody {
+				t.Errorf("Expected body %q, got %q", tc.expectedBody, receivedBody)
+			}
+			if tc.err != nil {
+				return
+			}
+			if resp.StatusCode != 201 || resp.ID != "page:home" || resp.Rev != "2-b" || string(resp.Body) != "3" {
+				t.Errorf("Unexpected response %+v", resp)
+			}
+		})
+	}
+}
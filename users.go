@@ -0,0 +1,166 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+const (
+	usersDBName  = "_users"
+	userIDPrefix = "org.couchdb.user:"
+)
+
+// User is a CouchDB user, as stored in the _users database.
+type User struct {
+	Name     string   `json:"name"`
+	Roles    []string `json:"roles"`
+	Password string   `json:"password,omitempty"` // Clear-text password, only sent when creating a user; CouchDB stores its hash
+}
+
+// Users manages the users of the server, stored in the _users database.
+// It requires server admin privileges.
+type Users struct {
+	db *Database
+}
+
+// Users returns the handle for managing the users of the server.
+//
+// Example:
+//
+//	err := cs.Users().CreateUser(ctx, couchdb.User{Name: "jane", Password: "s3cret", Roles: []string{"tenant-42"}})
+//	if err != nil {
+//	    log.Fatalf("Error creating user: %v", err)
+//	}
+func (c *CouchService) Users() *Users {
+	return &Users{db: &Database{httpClient: c.httpClient, dbName: usersDBName}}
+}
+
+// userID returns the ID of the _users document of the user.
+func userID(name string) string {
+	return userIDPrefix + name
+}
+
+// CreateUser creates a user. The password is hashed by the server.
+//
+// Parameters:
+//   - ctx: The context.Context for the HTTP request.
+//   - user: The user to create. Name and Password are required.
+//
+// Returns:
+//   - An error, if any, encountered while creating the user, e.g. if a user with the same name already exists.
+func (u *Users) CreateUser(ctx context.Context, user User) error {
+	if user.Name == "" || user.Password == "" {
+		return fmt.Errorf("user name and password are required")
+	}
+	if user.Roles == nil {
+		user.Roles = []string{}
+	}
+
+	doc := map[string]any{
+		"_id":      userID(user.Name),
+		"name":     user.Name,
+		"type":     "user",
+		"roles":    user.Roles,
+		"password": user.Password,
+	}
+	return u.putUserDoc(ctx, doc)
+}
+
+// GetUser returns a user. The password hash is never included.
+//
+// Returns:
+//   - *User: The user.
+//   - error: ErrNotFound if the user doesn't exist, or any other error encountered.
+func (u *Users) GetUser(ctx context.Context, name string) (*User, error) {
+	var user User
+	if err := u.db.getDoc(ctx, userID(name), GetDocOptions{}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser replaces the roles of a user, keeping their credentials.
+// To change the password, use ChangePassword; user.Password is ignored.
+//
+// Returns:
+//   - An error, if any, encountered while updating the user. ErrNotFound is returned if the user doesn't exist.
+func (u *Users) UpdateUser(ctx context.Context, user User) error {
+	if user.Roles == nil {
+		user.Roles = []string{}
+	}
+	return u.updateUserDoc(ctx, user.Name, func(doc map[string]any) {
+		doc["roles"] = user.Roles
+	})
+}
+
+// ChangePassword sets a new password for a user. The password is hashed by the server.
+//
+// Returns:
+//   - An error, if any, encountered while updating the user. ErrNotFound is returned if the user doesn't exist.
+func (u *Users) ChangePassword(ctx context.Context, name, password string) error {
+	if password == "" {
+		return fmt.Errorf("password is required")
+	}
+	return u.updateUserDoc(ctx, name, func(doc map[string]any) {
+		doc["password"] = password
+	})
+}
+
+// DeleteUser deletes a user.
+//
+// Returns:
+//   - An error, if any, encountered while deleting the user. ErrNotFound is returned if the user doesn't exist.
+func (u *Users) DeleteUser(ctx context.Context, name string) error {
+	var doc Document
+	if err := u.db.getDoc(ctx, userID(name), GetDocOptions{}, &doc); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("error getting user to delete: %w", err)
+	}
+
+	values := url.Values{}
+	values.Set("rev", doc.Rev)
+
+	respCode, respBody, err := u.db.httpClient.Delete(ctx, withQuery(docPath(usersDBName, userID(name)), values))
+	if err != nil {
+		return fmt.Errorf("error deleting user: %w", err)
+	}
+
+	if respCode != 200 && respCode != 202 {
+		return responseError("error deleting user", respCode, respBody)
+	}
+
+	return nil
+}
+
+// updateUserDoc applies update to the current _users document of the user and writes it back.
+// The document is modified as a map so the stored credentials and any unknown fields are preserved.
+func (u *Users) updateUserDoc(ctx context.Context, name string, update func(doc map[string]any)) error {
+	var doc map[string]any
+	if err := u.db.getDoc(ctx, userID(name), GetDocOptions{}, &doc); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("error getting user to update: %w", err)
+	}
+
+	update(doc)
+	return u.putUserDoc(ctx, doc)
+}
+
+// putUserDoc writes a _users document.
+func (u *Users) putUserDoc(ctx context.Context, doc map[string]any) error {
+	respCode, respBody, err := u.db.httpClient.Put(ctx, docPath(usersDBName, fmt.Sprint(doc["_id"])), doc)
+	if err != nil {
+		return fmt.Errorf("error writing user: %w", err)
+	}
+
+	if respCode != 201 && respCode != 202 {
+		return responseError("error writing user", respCode, respBody)
+	}
+
+	return nil
+}
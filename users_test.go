@@ -0,0 +1,49 @@
+package couchdb
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUsersKeepCredentialsOnUpdate(t *testing.T) {
+	fake := &fakeDocServer{docs: map[string]map[string]any{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cs := &CouchService{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second)}
+	users := cs.Users()
+	ctx := context.Background()
+
+	if err := users.CreateUser(ctx, User{Name: "jane", Password: "s3cret"}); err != nil {
+		t.Fatalf("Unexpected error creating user: %v", err)
+	}
+
+	// The server replaces the clear-text password with its hash.
+	stored := fake.docs["org.couchdb.user:jane"]
+	if stored == nil {
+		t.Fatalf("Expected user document to be stored under its org.couchdb.user: ID, got %v", fake.docs)
+	}
+	if stored["type"] != "user" {
+		t.Errorf("Expected type user, got %v", stored["type"])
+	}
+	delete(stored, "password")
+	stored["derived_key"] = "abc"
+
+	if err := users.UpdateUser(ctx, User{Name: "jane", Roles: []string{"admin"}}); err != nil {
+		t.Fatalf("Unexpected error updating user: %v", err)
+	}
+
+	updated := fake.docs["org.couchdb.user:jane"]
+	if updated["derived_key"] != "abc" {
+		t.Errorf("Expected credentials to be preserved, got %v", updated)
+	}
+	if _, ok := updated["password"]; ok {
+		t.Errorf("Expected no password to be sent on update, got %v", updated)
+	}
+	user, err := users.GetUser(ctx, "jane")
+	if err != nil || len(user.Roles) != 1 || user.Roles[0] != "admin" {
+		t.Errorf("Unexpected user: %+v, %v", user, err)
+	}
+}
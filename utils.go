@@ -1,12 +1,13 @@
 package couchdb
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
 	"net/url"
 	"reflect"
 	"regexp"
+	"strings"
 )
 
 var ErrMissingID = errors.New("missing _id field")
@@ -105,13 +106,42 @@ func isValidParam(param interface{}) bool {
 	}
 }
 
-// isValidURLScheme checks if a given string represents a valid URL scheme.
-func isValidURLScheme(s string) bool {
-	parsedURL, err := url.Parse(s)
-	if err != nil || parsedURL.Scheme == "" {
-		return false
+// setDocMeta writes the ID and revision returned by a write back onto the caller's document, so it can be
+// updated again without being read first. It handles maps with string keys and pointers to them, and pointers
+// to Document or to structs embedding it; any other value is left untouched. An empty id keeps the current one.
+func setDocMeta(doc any, id, rev string) {
+	value := reflect.ValueOf(doc)
+	if value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Kind() == reflect.Map {
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		if value.IsNil() || value.Type().Key().Kind() != reflect.String || !reflect.TypeOf(rev).AssignableTo(value.Type().Elem()) {
+			return
+		}
+		if id != "" {
+			value.SetMapIndex(reflect.ValueOf("_id").Convert(value.Type().Key()), reflect.ValueOf(id))
+		}
+		value.SetMapIndex(reflect.ValueOf("_rev").Convert(value.Type().Key()), reflect.ValueOf(rev))
+	case reflect.Ptr:
+		if value.IsNil() || value.Elem().Kind() != reflect.Struct {
+			return
+		}
+		var meta *Document
+		if d, ok := doc.(*Document); ok {
+			meta = d
+		} else if field := value.Elem().FieldByName("Document"); field.IsValid() && field.Type() == reflect.TypeOf(Document{}) && field.CanAddr() {
+			meta = field.Addr().Interface().(*Document)
+		}
+		if meta == nil {
+			return
+		}
+		if id != "" {
+			meta.ID = id
+		}
+		meta.Rev = rev
 	}
-	return true
 }
 
 // formAuthenticatedURL forms a URL with the provided base URL, username, and password.
@@ -132,22 +162,19 @@ func formAuthenticatedURL(baseURL, username, password string) (string, error) {
 	return authURL, nil
 }
 
-// testURLWithHEAD sends a HEAD request to the specified URL and checks the response status code.
-// It returns true if the response status code is within the 200-299 range, indicating a successful request.
-func testURLWithHEAD(url string) error {
-	// Send a HEAD request to the URL
-	resp, err := http.Head(url)
+// ping sends a single HEAD request to the root of the server through the client, so that the configured
+// Authenticator is applied and expired credentials renewed, and checks that it succeeds.
+func (c *CustomHTTPClient) ping(ctx context.Context) error {
+	resp, err := c.do(ctx, &request{method: "HEAD", endpoint: "", noRetry: true})
 	if err != nil {
 		return fmt.Errorf("error sending HEAD request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Check the response status code
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return nil
+	if resp.statusCode < 200 || resp.statusCode >= 300 {
+		return responseError("invalid response status code", resp.statusCode, resp.body)
 	}
 
-	return errors.New("invalid response status code")
+	return nil
 }
 
 // addSlashIfNeeded checks if the last character of a string is a slash '/' and adds it if it's not present.
@@ -158,3 +185,52 @@ func addSlashIfNeeded(s string) string {
 	}
 	return s
 }
+
+// withQuery appends the encoded query parameters to an endpoint, leaving it untouched if there are none.
+func withQuery(endpoint string, values url.Values) string {
+	if len(values) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + values.Encode()
+}
+
+// reservedDocPrefixes are the prefixes of document IDs whose slash CouchDB expects unescaped in paths.
+var reservedDocPrefixes = []string{"_design/", "_local/"}
+
+// escapePathSegment escapes a document ID or an attachment name for use as a single path segment.
+// Unlike url.PathEscape, it also escapes "+", which CouchDB decodes as a space.
+func escapePathSegment(segment string) string {
+	return strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+}
+
+// escapeDocID escapes a document ID for use in a path, so that IDs containing "/", "?", "#" or spaces
+// address the right document. The slash of the _design/ and _local/ prefixes is kept.
+func escapeDocID(id string) string {
+	for _, prefix := range reservedDocPrefixes {
+		if strings.HasPrefix(id, prefix) {
+			return prefix + escapePathSegment(strings.TrimPrefix(id, prefix))
+		}
+	}
+	return escapePathSegment(id)
+}
+
+// docPath returns the path of the document with the given ID in the database.
+func docPath(dbName, id string) string {
+	return fmt.Sprintf("%s/%s", dbName, escapeDocID(id))
+}
+
+// designPath returns the path of an endpoint of a design document under base, the path of a database or of one of
+// its partitions, e.g. "db/_design/orders/_view/by_date" for designPath("db", "orders", "_view", "by_date").
+// The design document may be named with or without its "_design/" prefix; the name and every segment are escaped.
+func designPath(base, design string, segments ...string) string {
+	path := fmt.Sprintf("%s/_design/%s", base, escapePathSegment(strings.TrimPrefix(design, "_design/")))
+	for _, segment := range segments {
+		path += "/" + escapePathSegment(segment)
+	}
+	return path
+}
+
+// attachmentPath returns the path of an attachment of the document with the given ID in the database.
+func attachmentPath(dbName, docID, name string) string {
+	return fmt.Sprintf("%s/%s", docPath(dbName, docID), escapePathSegment(name))
+}
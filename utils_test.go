@@ -151,3 +151,87 @@ func TestAddSlashIfNeeded(t *testing.T) {
 		})
 	}
 }
+
+func TestSetDocMeta(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      interface{}
+		id       string
+		expected interface{}
+	}{
+		{name: "map", doc: map[string]interface{}{"name": "a"}, id: "doc1", expected: map[string]interface{}{"_id": "doc1", "_rev": "2-x", "name": "a"}},
+		{name: "map keeps id when empty", doc: map[string]interface{}{"_id": "doc1"}, id: "", expected: map[string]interface{}{"_id": "doc1", "_rev": "2-x"}},
+		{name: "pointer to map", doc: &map[string]interface{}{}, id: "doc1", expected: &map[string]interface{}{"_id": "doc1", "_rev": "2-x"}},
+		{name: "map of strings", doc: map[string]string{}, id: "doc1", expected: map[string]string{"_id": "doc1", "_rev": "2-x"}},
+		{name: "map of ints is untouched", doc: map[string]int{}, id: "doc1", expected: map[string]int{}},
+		{name: "embedded document", doc: &Base{Name: "a"}, id: "doc1", expected: &Base{Document: Document{ID: "doc1", Rev: "2-x"}, Name: "a"}},
+		{name: "document", doc: &Document{ID: "doc1"}, id: "", expected: &Document{ID: "doc1", Rev: "2-x"}},
+		{name: "struct value is untouched", doc: Base{Name: "a"}, id: "doc1", expected: Base{Name: "a"}},
+		{name: "struct without document is untouched", doc: &struct{ Name string }{}, id: "doc1", expected: &struct{ Name string }{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setDocMeta(test.doc, test.id, "2-x")
+			if !reflect.DeepEqual(test.doc, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, test.doc)
+			}
+		})
+	}
+}
+
+func TestEscapeDocID(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"plain-id", "plain-id"},
+		{"user@example.com", "user@example.com"},
+		{"org.couchdb.user:jan", "org.couchdb.user:jan"},
+		{"https://example.com/a?b=c#d", "https:%2F%2Fexample.com%2Fa%3Fb=c%23d"},
+		{"a+b c", "a%2Bb%20c"},
+		{"_design/orders", "_design/orders"},
+		{"_design/a/b", "_design/a%2Fb"},
+		{"_local/checkpoint 1", "_local/checkpoint%201"},
+		{"_other/id", "_other%2Fid"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Input: %s", tc.input), func(t *testing.T) {
+			output := escapeDocID(tc.input)
+			if output != tc.expected {
+				t.Errorf("Expected: %s, Got: %s", tc.expected, output)
+			}
+		})
+	}
+}
+
+func TestAttachmentPath(t *testing.T) {
+	output := attachmentPath("test", "a/b", "c d/e+f.png")
+	expected := "test/a%2Fb/c%20d%2Fe%2Bf.png"
+	if output != expected {
+		t.Errorf("Expected: %s, Got: %s", expected, output)
+	}
+}
+
+func TestDesignPath(t *testing.T) {
+	testCases := []struct {
+		name     string
+		base     string
+		design   string
+		segments []string
+		expected string
+	}{
+		{name: "view", base: "test", design: "orders", segments: []string{"_view", "by_date"}, expected: "test/_design/orders/_view/by_date"},
+		{name: "prefixed design document", base: "test", design: "_design/orders", segments: []string{"_info"}, expected: "test/_design/orders/_info"},
+		{name: "reserved characters", base: "test", design: "a/b", segments: []string{"_view", "c d+e?"}, expected: "test/_design/a%2Fb/_view/c%20d%2Be%3F"},
+		{name: "partition", base: partitionPath("test", "sensor/1"), design: "orders", segments: []string{"_view", "all"}, expected: "test/_partition/sensor%2F1/_design/orders/_view/all"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if output := designPath(tc.base, tc.design, tc.segments...); output != tc.expected {
+				t.Errorf("Expected: %s, Got: %s", tc.expected, output)
+			}
+		})
+	}
+}
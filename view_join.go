@@ -0,0 +1,111 @@
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// JoinedDoc holds a parent document together with the child documents collated right after it in a view.
+type JoinedDoc[P, C any] struct {
+	ParentID string // First element of the join key shared by the parent and its children
+	Parent   P      // Parent document; the zero value if the view returned children without their parent
+	Children []C    // Child documents, in view order
+}
+
+// joinRow is a single row of a view following the collation join pattern.
+type joinRow struct {
+	ID    string            `json:"id"`
+	Key   []json.RawMessage `json:"key"`
+	Value json.RawMessage   `json:"value"`
+	Doc   json.RawMessage   `json:"doc"`
+}
+
+// ViewJoin queries a view built for the classic CouchDB "view collation" join and reassembles its rows
+// into parents with their nested children.
+//
+// The view must emit [parentID, 0] for every parent and [parentID, 1] (or any other non-zero second element)
+// for every child, so that collation places each parent immediately before its children:
+//
+//	function (doc) {
+//	    if (doc.type === "post") emit([doc._id, 0], null);
+//	    if (doc.type === "comment") emit([doc.post_id, 1], null);
+//	}
+//
+// Each row is decoded from its "doc" when the query includes documents (params.IncludeDocs),
+// and from its "value" otherwise.
+//
+// Parameters:
+//   - ctx: The context for the HTTP request.
+//   - db: The database holding the view.
+//   - design: The design document name.
+//   - view: The name of the view within the design document.
+//   - params: The parameters for the view query, as accepted by View.
+//
+// Returns:
+//   - []JoinedDoc[P, C]: One entry per parent ID, in view order.
+//   - error: An error if the view query fails or a row cannot be decoded into P or C.
+//
+// Example:
+//
+//	posts, err := couchdb.ViewJoin[Post, Comment](ctx, db, "blog", "posts_with_comments", couchdb.ViewParams{
+//	    IncludeDocs: true,
+//	})
+func ViewJoin[P, C any](ctx context.Context, db *Database, design, view string, params ViewParams) ([]JoinedDoc[P, C], error) {
+	var result struct {
+		Rows []joinRow `json:"rows"`
+	}
+	if err := db.View(ctx, design, view, params, &result); err != nil {
+		return nil, err
+	}
+
+	return joinRows[P, C](result.Rows)
+}
+
+// joinRows groups consecutive rows sharing the same parent ID, decoding the row flagged with 0 as the parent
+// and every other row as a child.
+func joinRows[P, C any](rows []joinRow) ([]JoinedDoc[P, C], error) {
+	var joined []JoinedDoc[P, C]
+	var current *JoinedDoc[P, C]
+
+	for _, row := range rows {
+		if len(row.Key) != 2 {
+			return nil, fmt.Errorf("invalid join key for row %q: expected [parentID, n], got %d elements", row.ID, len(row.Key))
+		}
+
+		var parentID string
+		if err := json.Unmarshal(row.Key[0], &parentID); err != nil {
+			return nil, fmt.Errorf("invalid parent ID in join key for row %q: %w", row.ID, err)
+		}
+		var position float64
+		if err := json.Unmarshal(row.Key[1], &position); err != nil {
+			return nil, fmt.Errorf("invalid position in join key for row %q: %w", row.ID, err)
+		}
+
+		if current == nil || current.ParentID != parentID {
+			joined = append(joined, JoinedDoc[P, C]{ParentID: parentID})
+			current = &joined[len(joined)-1]
+		}
+
+		payload := row.Value
+		if len(row.Doc) > 0 && !bytes.Equal(row.Doc, []byte("null")) {
+			payload = row.Doc
+		}
+
+		if position == 0 {
+			if err := json.Unmarshal(payload, &current.Parent); err != nil {
+				return nil, fmt.Errorf("error unmarshalling parent %q: %w", parentID, err)
+			}
+			continue
+		}
+
+		var child C
+		if err := json.Unmarshal(payload, &child); err != nil {
+			return nil, fmt.Errorf("error unmarshalling child %q of parent %q: %w", row.ID, parentID, err)
+		}
+		current.Children = append(current.Children, child)
+	}
+
+	return joined, nil
+}
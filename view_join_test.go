@@ -0,0 +1,80 @@
+package couchdb
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type joinPost struct {
+	Title string `json:"title"`
+}
+
+type joinComment struct {
+	Text string `json:"text"`
+}
+
+func TestJoinRows(t *testing.T) {
+	parseRows := func(t *testing.T, raw string) []joinRow {
+		var rows []joinRow
+		if err := json.Unmarshal([]byte(raw), &rows); err != nil {
+			t.Fatalf("invalid test rows: %v", err)
+		}
+		return rows
+	}
+
+	tests := []struct {
+		name      string
+		rows      string
+		expected  []JoinedDoc[joinPost, joinComment]
+		shouldErr bool
+	}{
+		{
+			name: "parents with children from docs",
+			rows: `[
+				{"id":"p1","key":["p1",0],"value":null,"doc":{"title":"first"}},
+				{"id":"c1","key":["p1",1],"value":null,"doc":{"text":"a"}},
+				{"id":"c2","key":["p1",1],"value":null,"doc":{"text":"b"}},
+				{"id":"p2","key":["p2",0],"value":null,"doc":{"title":"second"}}
+			]`,
+			expected: []JoinedDoc[joinPost, joinComment]{
+				{ParentID: "p1", Parent: joinPost{Title: "first"}, Children: []joinComment{{Text: "a"}, {Text: "b"}}},
+				{ParentID: "p2", Parent: joinPost{Title: "second"}},
+			},
+		},
+		{
+			name: "rows decoded from values",
+			rows: `[
+				{"id":"p1","key":["p1",0],"value":{"title":"first"}},
+				{"id":"c1","key":["p1",1],"value":{"text":"a"}}
+			]`,
+			expected: []JoinedDoc[joinPost, joinComment]{
+				{ParentID: "p1", Parent: joinPost{Title: "first"}, Children: []joinComment{{Text: "a"}}},
+			},
+		},
+		{
+			name: "children without parent",
+			rows: `[{"id":"c1","key":["p1",1],"value":{"text":"a"}}]`,
+			expected: []JoinedDoc[joinPost, joinComment]{
+				{ParentID: "p1", Children: []joinComment{{Text: "a"}}},
+			},
+		},
+		{
+			name:      "key with a single element",
+			rows:      `[{"id":"p1","key":["p1"],"value":null}]`,
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := joinRows[joinPost, joinComment](parseRows(t, tt.rows))
+			if (err != nil) != tt.shouldErr {
+				t.Fatalf("Expected error: %v, Got error: %v", tt.shouldErr, err)
+			}
+			if !tt.shouldErr && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("joinRows() got = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RowIterator reads the rows of a view response one at a time as they arrive, as returned by ViewRows.
+// Only the current row is held in memory, so views of any size can be processed with constant memory.
+// It must be closed once done with, unless Next has returned false.
+type RowIterator struct {
+	TotalRows int // Number of rows in the view, regardless of the query parameters
+	Offset    int // Offset of the first row in the view
+
+	body    io.ReadCloser
+	decoder *json.Decoder
+	row     json.RawMessage
+	err     error
+	done    bool
+}
+
+// ViewRows queries a view like View, but instead of unmarshalling the whole result at once it returns an iterator
+// which decodes the rows from the response stream one at a time. Like other streamed responses, the query is neither
+// retried nor bound by the client timeout, so its lifetime is controlled by ctx.
+//
+// Parameters:
+//   - ctx: The context for the HTTP request, which must stay alive while the rows are read.
+//   - design: The design document name.
+//   - view: The name of the view within the design document.
+//   - params: The parameters for the view query.
+//   - opts: Optional per-request options, e.g. WithHeader.
+//
+// Returns:
+//   - *RowIterator: The iterator over the rows of the view, positioned before the first row.
+//   - error: An error if the view query fails.
+//
+// Example:
+//
+//	rows, err := db.ViewRows(ctx, "orders", "by_date", couchdb.ViewParams{IncludeDocs: true})
+//	if err != nil {
+//	    log.Fatalf("Error querying view: %v", err)
+//	}
+//	defer rows.Close()
+//	for rows.Next() {
+//	    var row struct {
+//	        ID  string `json:"id"`
+//	        Doc Order  `json:"doc"`
+//	    }
+//	    if err := rows.Scan(&row); err != nil {
+//	        log.Fatalf("Error decoding row: %v", err)
+//	    }
+//	    process(row.Doc)
+//	}
+//	if err := rows.Err(); err != nil {
+//	    log.Fatalf("Error reading view: %v", err)
+//	}
+func (db *Database) ViewRows(ctx context.Context, design, view string, params ViewParams, opts ...RequestOption) (*RowIterator, error) {
+	r := &request{
+		method:   "POST",
+		endpoint: designPath(db.dbName, design, "_view", view),
+		body:     params,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	resp, err := db.httpClient.stream(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("error getting view: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, responseError("error getting view", resp.StatusCode, respBody)
+	}
+
+	rows := &RowIterator{body: resp.Body, decoder: json.NewDecoder(resp.Body)}
+	if err := rows.readHeader(); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("error reading view response: %w", err)
+	}
+
+	return rows, nil
+}
+
+// readHeader reads the fields of the response preceding the rows, leaving the decoder at the first row.
+func (it *RowIterator) readHeader() error {
+	if err := it.expectDelim('{'); err != nil {
+		return err
+	}
+
+	for it.decoder.More() {
+		key, err := it.readKey()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "rows":
+			return it.expectDelim('[')
+		case "total_rows":
+			err = it.decoder.Decode(&it.TotalRows)
+		case "offset":
+			err = it.decoder.Decode(&it.Offset)
+		default:
+			err = it.decoder.Decode(&json.RawMessage{})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// A response without rows, e.g. with a zero limit on some CouchDB versions.
+	it.done = true
+	return nil
+}
+
+// readTrailer reads the fields of the response following the rows, which report errors the server ran into
+// after it had started sending the rows.
+func (it *RowIterator) readTrailer() error {
+	if err := it.expectDelim(']'); err != nil {
+		return err
+	}
+
+	var couchErr struct {
+		Error  string `json:"error"`
+		Reason string `json:"reason"`
+	}
+	for it.decoder.More() {
+		key, err := it.readKey()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "error":
+			err = it.decoder.Decode(&couchErr.Error)
+		case "reason":
+			err = it.decoder.Decode(&couchErr.Reason)
+		default:
+			err = it.decoder.Decode(&json.RawMessage{})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if couchErr.Error != "" {
+		return fmt.Errorf("server failed while sending rows: %s: %s", couchErr.Error, couchErr.Reason)
+	}
+
+	return it.expectDelim('}')
+}
+
+// readKey reads the key of the next field of an object.
+func (it *RowIterator) readKey() (string, error) {
+	token, err := it.decoder.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := token.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected token %v", token)
+	}
+	return key, nil
+}
+
+// expectDelim reads the next token, which must be the given delimiter.
+func (it *RowIterator) expectDelim(delim json.Delim) error {
+	token, err := it.decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %v, got %v", delim, token)
+	}
+	return nil
+}
+
+// Next advances the iterator to the next row, reporting whether there is one. It returns false once all the rows
+// have been read, or if an error occurred, which Err then returns; the response is closed in both cases.
+func (it *RowIterator) Next() bool {
+	if it.done || it.err != nil {
+		it.finish()
+		return false
+	}
+
+	if !it.decoder.More() {
+		if err := it.readTrailer(); err != nil {
+			it.err = fmt.Errorf("error reading view response: %w", err)
+		}
+		it.finish()
+		return false
+	}
+
+	if err := it.decoder.Decode(&it.row); err != nil {
+		it.err = fmt.Errorf("error decoding view row: %w", err)
+		it.finish()
+		return false
+	}
+
+	return true
+}
+
+// Scan unmarshals the current row into dest, e.g. a struct with "id", "key", "value" and "doc" JSON fields.
+// The row is only valid until the next call to Next.
+func (it *RowIterator) Scan(dest any) error {
+	if it.row == nil || it.done || it.err != nil {
+		return errors.New("no current row: Scan must be called after a successful Next")
+	}
+	if err := json.Unmarshal(it.row, dest); err != nil {
+		return fmt.Errorf("error unmarshalling view row: %w", err)
+	}
+	return nil
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *RowIterator) Err() error {
+	return it.err
+}
+
+// Close closes the response, stopping the iteration. It can be called several times.
+func (it *RowIterator) Close() error {
+	it.done = true
+	body := it.body
+	it.body = nil
+	if body == nil {
+		return nil
+	}
+	return body.Close()
+}
+
+// finish marks the iteration as done and closes the response, draining what is left of it after the rows.
+func (it *RowIterator) finish() {
+	it.done = true
+	if it.body != nil {
+		drainAndClose(it.body)
+		it.body = nil
+	}
+}
@@ -0,0 +1,137 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestViewRows(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/test/_design/orders/_view/by_date":
+			w.Write([]byte(`{"total_rows":3,"offset":1,"rows":[`))
+			for i := 1; i <= 2; i++ {
+				if i > 1 {
+					w.Write([]byte(","))
+				}
+				fmt.Fprintf(w, `{"id":"order-%d","key":"2024-05-0%d","value":%d}`, i, i, i*10)
+				w.(http.Flusher).Flush()
+			}
+			w.Write([]byte(`]}`))
+		case "/test/_design/orders/_view/empty":
+			w.Write([]byte(`{"total_rows":0,"offset":0,"rows":[]}`))
+		case "/test/_design/orders/_view/failing":
+			w.Write([]byte(`{"total_rows":3,"offset":0,"rows":[{"id":"order-1","key":"a","value":1}],"error":"timeout","reason":"The request could not be processed in a reasonable amount of time."}`))
+		case "/test/_design/orders/_view/truncated":
+			w.Write([]byte(`{"total_rows":3,"offset":0,"rows":[{"id":"order-1","key":"a","value":1},{"id":"ord`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not_found","reason":"missing_named_view"}`))
+		}
+	}))
+	defer server.Close()
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	testCases := []struct {
+		name      string
+		view      string
+		totalRows int
+		expected  []string
+		openErr   error
+		iterErr   string
+	}{
+		{name: "rows", view: "by_date", totalRows: 3, expected: []string{"order-1:10", "order-2:20"}},
+		{name: "no rows", view: "empty"},
+		{name: "error after rows", view: "failing", totalRows: 3, expected: []string{"order-1:1"}, iterErr: "timeout"},
+		{name: "truncated response", view: "truncated", totalRows: 3, expected: []string{"order-1:1"}, iterErr: "error decoding view row"},
+		{name: "missing view", view: "missing", openErr: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := db.ViewRows(context.Background(), "orders", tc.view, ViewParams{})
+			if !errors.Is(err, tc.openErr) {
+				t.Fatalf("Expected error %v, got %v", tc.openErr, err)
+			}
+			if tc.openErr != nil {
+				return
+			}
+			defer rows.Close()
+
+			if rows.TotalRows != tc.totalRows {
+				t.Errorf("Expected %d total rows, got %d", tc.totalRows, rows.TotalRows)
+			}
+
+			var got []string
+			for rows.Next() {
+				var row struct {
+					ID    string `json:"id"`
+					Value int    `json:"value"`
+				}
+				if err := rows.Scan(&row); err != nil {
+					t.Fatalf("Unexpected error scanning row: %v", err)
+				}
+				got = append(got, fmt.Sprintf("%s:%d", row.ID, row.Value))
+			}
+			if strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("Expected rows %v, got %v", tc.expected, got)
+			}
+
+			if tc.iterErr == "" && rows.Err() != nil {
+				t.Errorf("Unexpected error: %v", rows.Err())
+			}
+			if tc.iterErr != "" && (rows.Err() == nil || !strings.Contains(rows.Err().Error(), tc.iterErr)) {
+				t.Errorf("Expected error containing %q, got %v", tc.iterErr, rows.Err())
+			}
+			if rows.Next() {
+				t.Error("Expected Next to keep returning false once done")
+			}
+			if err := rows.Scan(&struct{}{}); err == nil {
+				t.Error("Expected an error scanning without a current row")
+			}
+		})
+	}
+}
+
+func TestViewRowsClose(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total_rows":2,"offset":0,"rows":[{"id":"order-1","key":"a","value":1},`))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	db := &Database{httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second), dbName: "test"}
+
+	rows, err := db.ViewRows(context.Background(), "orders", "by_date", ViewParams{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !rows.Next() {
+		t.Fatalf("Expected a first row, got error %v", rows.Err())
+	}
+	if err := rows.Close(); err != nil {
+		t.Errorf("Unexpected error closing: %v", err)
+	}
+	if rows.Next() {
+		t.Error("Expected Next to return false after Close")
+	}
+	if err := rows.Close(); err != nil {
+		t.Errorf("Unexpected error closing twice: %v", err)
+	}
+}
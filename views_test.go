@@ -1,7 +1,14 @@
 package couchdb
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 )
 
 // Define a test struct for holding test cases
@@ -75,6 +82,87 @@ func TestCheckStructForJSONFields(t *testing.T) {
 	}
 }
 
+func TestViewParamsJSON(t *testing.T) {
+	noReduce := false
+	testCases := []struct {
+		Name     string
+		Params   ViewParams
+		Expected string
+	}{
+		{Name: "Zero params", Params: ViewParams{}, Expected: `{}`},
+		{Name: "Empty string key is kept", Params: ViewParams{Key: ""}, Expected: `{"key":""}`},
+		{
+			Name:     "Complex key range",
+			Params:   ViewParams{StartKey: []any{"a", 1}, EndKey: []any{"a", map[string]any{}}, Limit: 10},
+			Expected: `{"start_key":["a",1],"end_key":["a",{}],"limit":10}`,
+		},
+		{Name: "Explicitly disabled reduce", Params: ViewParams{Reduce: &noReduce}, Expected: `{"reduce":false}`},
+		{
+			Name:     "Grouping",
+			Params:   ViewParams{Group: true, GroupLevel: 2},
+			Expected: `{"group":true,"group_level":2}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got, err := json.Marshal(tc.Params)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(got) != tc.Expected {
+				t.Errorf("Expected %s, Got %s", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestViewWithStaleFallback(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		FreshDelay    time.Duration
+		ExpectedStale bool
+	}{
+		{Name: "Fresh query answers in time", FreshDelay: 0, ExpectedStale: false},
+		{Name: "Fresh query times out", FreshDelay: 200 * time.Millisecond, ExpectedStale: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var params map[string]any
+				_ = json.NewDecoder(r.Body).Decode(&params)
+				if params["update"] != "false" {
+					select {
+					case <-time.After(tc.FreshDelay):
+					case <-r.Context().Done():
+						return
+					}
+				}
+				_, _ = w.Write([]byte(`{"total_rows":1,"offset":0,"rows":[{"id":"a","key":"a"}]}`))
+			}))
+			defer server.Close()
+
+			db := &Database{
+				httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second),
+				dbName:     "test",
+			}
+
+			var result validStruct
+			stale, err := db.ViewWithStaleFallback(context.Background(), "ddoc", "view", ViewParams{Limit: 1}, 50*time.Millisecond, &result)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if stale != tc.ExpectedStale {
+				t.Errorf("Expected stale: %v, Got stale: %v", tc.ExpectedStale, stale)
+			}
+			if len(result.Rows) != 1 {
+				t.Errorf("Expected 1 row, Got %d", len(result.Rows))
+			}
+		})
+	}
+}
+
 // Define sample structs for testing
 
 type validStruct struct {
@@ -151,3 +239,272 @@ type missingDocTagStruct struct {
 		Doc struct{} `json:"dock"`
 	} `json:"rows"`
 }
+
+func TestViewQueries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test/_design/ddoc/_view/view/queries" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		var body struct {
+			Queries []ViewParams `json:"queries"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Queries) != 2 {
+			t.Errorf("Unexpected body: %v, %v", body, err)
+		}
+		_, _ = w.Write([]byte(`{"results":[
+			{"total_rows":3,"offset":0,"rows":[{"id":"a","key":"a"}]},
+			{"total_rows":3,"offset":1,"rows":[{"id":"b","key":"b"},{"id":"c","key":"c"}]}]}`))
+	}))
+	defer server.Close()
+
+	db := &Database{
+		httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second),
+		dbName:     "test",
+	}
+
+	t.Run("One result per query", func(t *testing.T) {
+		var results []validStruct
+		err := db.ViewQueries(context.Background(), "ddoc", "view", []ViewParams{{Key: "a"}, {StartKey: "b"}}, &results)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(results) != 2 || len(results[0].Rows) != 1 || len(results[1].Rows) != 2 {
+			t.Errorf("Unexpected results: %+v", results)
+		}
+	})
+
+	t.Run("Invalid results type", func(t *testing.T) {
+		var results validStruct
+		if err := db.ViewQueries(context.Background(), "ddoc", "view", nil, &results); err == nil {
+			t.Errorf("Expected an error for a non-slice resultsVar")
+		}
+	})
+}
+
+func TestSyncDesignDoc(t *testing.T) {
+	current := `{"_id":"_design/ddoc","_rev":"1-a","language":"javascript","views":{"v":{"map":"function (doc) { emit(doc._id); }"}}}`
+
+	testCases := []struct {
+		Name            string
+		DesignDoc       DesignDocument
+		Exists          bool
+		ExpectedUpdated bool
+	}{
+		{
+			Name: "Unchanged design doc is not written",
+			DesignDoc: DesignDocument{Language: "javascript", Views: map[string]ViewDefinition{
+				"v": {Map: "function (doc) { emit(doc._id); }"},
+			}},
+			Exists:          true,
+			ExpectedUpdated: false,
+		},
+		{
+			Name: "Changed design doc is written",
+			DesignDoc: DesignDocument{Language: "javascript", Views: map[string]ViewDefinition{
+				"v": {Map: "function (doc) { emit(doc.name); }"},
+			}},
+			Exists:          true,
+			ExpectedUpdated: true,
+		},
+		{
+			Name:            "Missing design doc is created",
+			DesignDoc:       DesignDocument{Language: "javascript"},
+			Exists:          false,
+			ExpectedUpdated: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					if !tc.Exists {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					_, _ = w.Write([]byte(current))
+				case http.MethodPut:
+					var doc DesignDocument
+					_ = json.NewDecoder(r.Body).Decode(&doc)
+					if tc.Exists && doc.Rev != "1-a" {
+						t.Errorf("Expected current rev to be sent, got %q", doc.Rev)
+					}
+					w.WriteHeader(http.StatusCreated)
+				}
+			}))
+			defer server.Close()
+
+			db := &Database{
+				httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second),
+				dbName:     "test",
+			}
+
+			updated, err := db.SyncDesignDoc(context.Background(), "_design/ddoc", tc.DesignDoc)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if updated != tc.ExpectedUpdated {
+				t.Errorf("Expected updated: %v, Got updated: %v", tc.ExpectedUpdated, updated)
+			}
+		})
+	}
+}
+
+func TestGetDesignDoc(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET, got %s", r.Method)
+		}
+		if r.URL.Path != "/test/_design/ddoc" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"_id":"_design/ddoc","_rev":"3-c","language":"javascript","views":{"by_name":{"map":"function (doc) { emit(doc.name); }","reduce":"_count"}},"autoupdate":true}`))
+	}))
+	defer server.Close()
+
+	db := &Database{
+		httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second),
+		dbName:     "test",
+	}
+
+	expected := &DesignDocument{
+		ID:         "_design/ddoc",
+		Rev:        "3-c",
+		Language:   "javascript",
+		Views:      map[string]ViewDefinition{"by_name": {Map: "function (doc) { emit(doc.name); }", Reduce: "_count"}},
+		Autoupdate: true,
+	}
+
+	testCases := []struct {
+		Name          string
+		DesignDoc     string
+		Expected      *DesignDocument
+		ExpectedError error
+	}{
+		{Name: "Design doc by name", DesignDoc: "ddoc", Expected: expected},
+		{Name: "Design doc by prefixed name", DesignDoc: "_design/ddoc", Expected: expected},
+		{Name: "Missing design doc", DesignDoc: "missing", ExpectedError: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			designDoc, err := db.GetDesignDoc(context.Background(), tc.DesignDoc)
+			if !errors.Is(err, tc.ExpectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.ExpectedError, err)
+			}
+			if !reflect.DeepEqual(designDoc, tc.Expected) {
+				t.Errorf("Expected %+v, got %+v", tc.Expected, designDoc)
+			}
+		})
+	}
+}
+
+func TestDeleteDesignDoc(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Exists        bool
+		DeleteStatus  int
+		ExpectedError error
+	}{
+		{Name: "Existing design doc is deleted at its current rev", Exists: true, DeleteStatus: http.StatusOK},
+		{Name: "Missing design doc", Exists: false, ExpectedError: ErrNotFound},
+		{Name: "Design doc updated in between", Exists: true, DeleteStatus: http.StatusConflict, ExpectedError: ErrConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			deleted := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/test/_design/ddoc" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				switch r.Method {
+				case http.MethodGet:
+					if !tc.Exists {
+						w.WriteHeader(http.StatusNotFound)
+						_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+						return
+					}
+					_, _ = w.Write([]byte(`{"_id":"_design/ddoc","_rev":"2-b","language":"javascript"}`))
+				case http.MethodDelete:
+					if rev := r.URL.Query().Get("rev"); rev != "2-b" {
+						t.Errorf("Expected the current rev to be deleted, got %q", rev)
+					}
+					deleted = true
+					w.WriteHeader(tc.DeleteStatus)
+					if tc.DeleteStatus == http.StatusConflict {
+						_, _ = w.Write([]byte(`{"error":"conflict","reason":"Document update conflict."}`))
+						return
+					}
+					_, _ = w.Write([]byte(`{"ok":true,"id":"_design/ddoc","rev":"3-d"}`))
+				}
+			}))
+			defer server.Close()
+
+			db := &Database{
+				httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second),
+				dbName:     "test",
+			}
+
+			err := db.DeleteDesignDoc(context.Background(), "_design/ddoc")
+			if !errors.Is(err, tc.ExpectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.ExpectedError, err)
+			}
+			if deleted != tc.Exists {
+				t.Errorf("Expected deleted: %v, Got deleted: %v", tc.Exists, deleted)
+			}
+		})
+	}
+}
+
+func TestWarmViews(t *testing.T) {
+	var warmed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			switch r.URL.Path {
+			case "/test/_design/ddoc":
+				_, _ = w.Write([]byte(`{"_id":"_design/ddoc","_rev":"1-a","views":{"b":{"map":"m","reduce":"_count"},"a":{"map":"m"},"c":{"map":"m"}}}`))
+			default:
+				_, _ = w.Write([]byte(`{"_id":"_design/empty","_rev":"1-a","language":"javascript"}`))
+			}
+			return
+		}
+		var params map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&params)
+		if params["limit"] != float64(0) {
+			t.Errorf("Expected limit=0, got %v", params["limit"])
+		}
+		warmed = append(warmed, r.URL.Path)
+		_, _ = w.Write([]byte(`{"total_rows":0,"offset":0,"rows":[]}`))
+	}))
+	defer server.Close()
+
+	db := &Database{
+		httpClient: NewCustomHTTPClient(server.URL+"/", 1, time.Millisecond, time.Second),
+		dbName:     "test",
+	}
+
+	testCases := []struct {
+		Name           string
+		DesignDoc      string
+		ExpectedWarmed []string
+	}{
+		{Name: "Views share a single index", DesignDoc: "ddoc", ExpectedWarmed: []string{"/test/_design/ddoc/_view/a"}},
+		{Name: "Design doc without views", DesignDoc: "empty"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			warmed = nil
+			if err := db.WarmViews(context.Background(), tc.DesignDoc); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(warmed, tc.ExpectedWarmed) {
+				t.Errorf("Expected %v to be queried, got %v", tc.ExpectedWarmed, warmed)
+			}
+		})
+	}
+}